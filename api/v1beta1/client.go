@@ -23,6 +23,8 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -68,7 +70,114 @@ func GetDBClusterByType(
 			return &ovndb, nil
 		}
 	}
-	return nil, fmt.Errorf("failed to find DBCluster of type %s", dbType)
+	return nil, k8s_errors.NewNotFound(GroupVersion.WithResource("ovndbclusters").GroupResource(), dbType)
+}
+
+// GetDBClusterForRef - returns the referenced OVNDBCluster, or the one of the given type in the namespace
+// without reference
+func GetDBClusterForRef(
+	ctx context.Context,
+	h *helper.Helper,
+	ref *OVNDBClusterRef,
+	namespace string,
+	dbType string,
+) (*OVNDBCluster, error) {
+	if ref == nil {
+		return GetDBClusterByType(ctx, h, namespace, map[string]string{}, dbType)
+	}
+
+	cluster := &OVNDBCluster{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.GetNamespace(namespace)}, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Spec.DBType != dbType {
+		return nil, fmt.Errorf("referenced DBCluster %s/%s is of type %s instead of %s",
+			cluster.Namespace, cluster.Name, cluster.Spec.DBType, dbType)
+	}
+	return cluster, nil
+}
+
+// DBClusterConsumer - CRs connecting to the database of OVNDBClusters
+// +kubebuilder:object:generate=false
+type DBClusterConsumer interface {
+	client.Object
+	ConsumesDBCluster(cluster *OVNDBCluster) bool
+	IsTLSEnabled() bool
+}
+
+// getDBClusterConsumers - returns the OVNNorthd and OVNController CRs of all namespaces
+func getDBClusterConsumers(ctx context.Context, reader client.Reader) ([]DBClusterConsumer, error) {
+	consumers := []DBClusterConsumer{}
+
+	northdList := &OVNNorthdList{}
+	if err := reader.List(ctx, northdList); err != nil {
+		return nil, err
+	}
+	for i := range northdList.Items {
+		consumers = append(consumers, &northdList.Items[i])
+	}
+	controllerList := &OVNControllerList{}
+	if err := reader.List(ctx, controllerList); err != nil {
+		return nil, err
+	}
+	for i := range controllerList.Items {
+		consumers = append(consumers, &controllerList.Items[i])
+	}
+	return consumers, nil
+}
+
+// GetDBClusterDependents - returns the OVNNorthd and OVNController CRs, not being deleted, which connect to
+// the database of the OVNDBCluster
+func GetDBClusterDependents(
+	ctx context.Context,
+	reader client.Reader,
+	instance *OVNDBCluster,
+) ([]string, error) {
+	return getDBClusterDependents(ctx, reader, instance, false)
+}
+
+// GetDBClusterDependentsWithoutTLS - returns the dependents of the OVNDBCluster which have no client
+// certificate to connect to a database serving SSL
+func GetDBClusterDependentsWithoutTLS(
+	ctx context.Context,
+	reader client.Reader,
+	instance *OVNDBCluster,
+) ([]string, error) {
+	return getDBClusterDependents(ctx, reader, instance, true)
+}
+
+func getDBClusterDependents(
+	ctx context.Context,
+	reader client.Reader,
+	instance *OVNDBCluster,
+	withoutTLS bool,
+) ([]string, error) {
+	dependents := []string{}
+
+	consumers, err := getDBClusterConsumers(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	for _, consumer := range consumers {
+		if !consumer.GetDeletionTimestamp().IsZero() || !consumer.ConsumesDBCluster(instance) {
+			continue
+		}
+		if withoutTLS && consumer.IsTLSEnabled() {
+			continue
+		}
+		name := consumer.GetName()
+		if consumer.GetNamespace() != instance.Namespace {
+			name = consumer.GetNamespace() + "/" + name
+		}
+		kind := "OVNNorthd"
+		if _, ok := consumer.(*OVNController); ok {
+			kind = "OVNController"
+		}
+		dependents = append(dependents, kind+"/"+name)
+	}
+
+	return dependents, nil
 }
 
 func getItems(list client.ObjectList) []client.Object {
@@ -86,6 +195,59 @@ func getItems(list client.ObjectList) []client.Object {
 	return items
 }
 
+// OVNDBClusterConsumerMapFunc - DBCluster Watch Function enqueuing the CRs of any namespace which connect to
+// the database of the OVNDBCluster
+func OVNDBClusterConsumerMapFunc(crs client.ObjectList, reader client.Reader, log logr.Logger) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		cluster, ok := obj.(*OVNDBCluster)
+		if !ok {
+			return nil
+		}
+		if err := reader.List(context.Background(), crs); err != nil {
+			log.Error(err, "Unable to retrieve self CRs %v")
+			return nil
+		}
+		for _, cr := range getItems(crs) {
+			if consumer, ok := cr.(DBClusterConsumer); ok && consumer.ConsumesDBCluster(cluster) {
+				result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+		return nil
+	}
+}
+
+// DBClusterConsumerMapFunc - OVNNorthd and OVNController Watch Function enqueuing the OVNDBClusters of any
+// namespace they connect to
+func DBClusterConsumerMapFunc(reader client.Reader, log logr.Logger) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+
+		consumer, ok := obj.(DBClusterConsumer)
+		if !ok {
+			return nil
+		}
+		ovnDBList := &OVNDBClusterList{}
+		if err := reader.List(context.Background(), ovnDBList); err != nil {
+			log.Error(err, "Unable to retrieve OVNDBClusters")
+			return nil
+		}
+		for _, cluster := range ovnDBList.Items {
+			if consumer.ConsumesDBCluster(&cluster) {
+				result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+		return nil
+	}
+}
+
 // OVNDBClusterNamespaceMapFunc - DBCluster Watch Function
 func OVNDBClusterNamespaceMapFunc(crs client.ObjectList, reader client.Reader, log logr.Logger) handler.MapFunc {
 	return func(obj client.Object) []reconcile.Request {
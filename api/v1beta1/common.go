@@ -24,6 +24,7 @@ func SetupDefaults() {
 	ovnDbClusterDefaults := OVNDBClusterDefaults{
 		NBContainerImageURL: util.GetEnvVar("RELATED_IMAGE_OVN_NB_DBCLUSTER_IMAGE_URL_DEFAULT", OvnNBContainerImage),
 		SBContainerImageURL: util.GetEnvVar("RELATED_IMAGE_OVN_SB_DBCLUSTER_IMAGE_URL_DEFAULT", OvnSBContainerImage),
+		ClusterDomain:       util.GetEnvVar("OVN_CLUSTER_DOMAIN_DEFAULT", DefaultClusterDomain),
 	}
 
 	SetupOVNDBClusterDefaults(ovnDbClusterDefaults)
@@ -0,0 +1,185 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+)
+
+// OVN Condition Types used by API objects.
+const (
+	// OVNDBIntegrityCondition Status=True condition which indicates if the DB files of all members passed the
+	// last integrity check
+	OVNDBIntegrityCondition condition.Type = "OVNDBIntegrity"
+
+	// OVNDBDeletionReadyCondition Status=False condition which indicates the deletion of the OVNDBCluster
+	// is blocked by CRs still using the database
+	OVNDBDeletionReadyCondition condition.Type = "OVNDBDeletionReady"
+
+	// OVNDBClusterDegradedCondition Status=True condition which indicates the OVNDBCluster is serving with fewer
+	// members than desired. It is removed once all the members are up and joined.
+	OVNDBClusterDegradedCondition condition.Type = "Degraded"
+
+	// OVNDBReachableCondition Status=True condition which indicates if the database of an external mode
+	// OVNDBCluster accepts connections
+	OVNDBReachableCondition condition.Type = "OVNDBReachable"
+
+	// OVNDBMigrationCompletedCondition Status=True condition which indicates if the final copy of the data of
+	// an OVNDBMigration finished
+	OVNDBMigrationCompletedCondition condition.Type = "OVNDBMigrationCompleted"
+
+	// OVNGlobalConfigAppliedCondition Status=True condition which indicates if the NB_Global table holds the
+	// values of an OVNGlobalConfig
+	OVNGlobalConfigAppliedCondition condition.Type = "OVNGlobalConfigApplied"
+
+	// OVNNorthdNBDBReadyCondition Status=True condition which indicates if the NB OVNDBCluster of an OVNNorthd
+	// exists and publishes its endpoint
+	OVNNorthdNBDBReadyCondition condition.Type = "NBDBReady"
+
+	// OVNNorthdSBDBReadyCondition Status=True condition which indicates if the SB OVNDBCluster of an OVNNorthd
+	// exists and publishes its endpoint
+	OVNNorthdSBDBReadyCondition condition.Type = "SBDBReady"
+
+	// OVNNorthdPausedCondition Status=True condition which indicates the ovn-northd instances of a paused
+	// OVNNorthd are paused. It is removed once they are resumed.
+	OVNNorthdPausedCondition condition.Type = "Paused"
+
+	// OVNConfigLaggingCondition Status=True condition which indicates the NB configuration did not reach the SB
+	// database or the chassis within the lag threshold. It is removed once they caught up.
+	OVNConfigLaggingCondition condition.Type = "ConfigLagging"
+
+	// OVNTraceCompletedCondition Status=True condition which indicates if the result of an OVNTrace is stored
+	// in its result ConfigMap
+	OVNTraceCompletedCondition condition.Type = "OVNTraceCompleted"
+)
+
+// Common Messages used by API objects.
+const (
+	//
+	// OVNDBIntegrity condition messages
+	//
+	// OVNDBIntegrityReadyMessage
+	OVNDBIntegrityReadyMessage = "OVN DB integrity check passed"
+
+	// OVNDBIntegrityErrorMessage
+	OVNDBIntegrityErrorMessage = "OVN DB integrity check failed for members: %s"
+
+	//
+	// OVNDBDeletionReady condition messages
+	//
+	// OVNDBDeletionBlockedMessage
+	OVNDBDeletionBlockedMessage = "OVN DB deletion blocked by dependent resources: %s"
+
+	//
+	// Degraded condition messages
+	//
+	// OVNDBClusterDegradedMessage
+	OVNDBClusterDegradedMessage = "OVN DB cluster serving with %d of %d members ready"
+
+	//
+	// OVNDBReachable condition messages
+	//
+	// OVNDBReachableInitMessage
+	OVNDBReachableInitMessage = "External OVN DB connectivity not checked yet"
+
+	// OVNDBReachableMessage
+	OVNDBReachableMessage = "External OVN DB reachable"
+
+	// OVNDBReachableErrorMessage
+	OVNDBReachableErrorMessage = "External OVN DB not reachable: %s"
+
+	//
+	// OVNDBMigrationCompleted condition messages
+	//
+	// OVNDBMigrationCompletedInitMessage
+	OVNDBMigrationCompletedInitMessage = "OVN DB migration not started"
+
+	// OVNDBMigrationRunningMessage
+	OVNDBMigrationRunningMessage = "OVN DB migration in progress"
+
+	// OVNDBMigrationSyncingMessage
+	OVNDBMigrationSyncingMessage = "OVN DB migration syncing until cutover, %d syncs finished"
+
+	// OVNDBMigrationCompletedMessage
+	OVNDBMigrationCompletedMessage = "OVN DB migration completed"
+
+	// OVNDBMigrationErrorMessage
+	OVNDBMigrationErrorMessage = "OVN DB migration failed: %s"
+
+	//
+	// OVNGlobalConfigApplied condition messages
+	//
+	// OVNGlobalConfigAppliedInitMessage
+	OVNGlobalConfigAppliedInitMessage = "OVN global config not applied yet"
+
+	// OVNGlobalConfigAppliedMessage
+	OVNGlobalConfigAppliedMessage = "OVN global config applied"
+
+	// OVNGlobalConfigAppliedErrorMessage
+	OVNGlobalConfigAppliedErrorMessage = "OVN global config not applied: %s"
+
+	//
+	// NBDBReady and SBDBReady condition messages
+	//
+	// OVNDBClusterInitMessage
+	OVNDBClusterInitMessage = "OVN DB cluster not checked yet"
+
+	// OVNDBClusterReadyMessage
+	OVNDBClusterReadyMessage = "OVN %s DB cluster %s ready"
+
+	// OVNDBClusterMissingMessage
+	OVNDBClusterMissingMessage = "OVN %s DB cluster %s not found"
+
+	// OVNDBClusterWaitingMessage
+	OVNDBClusterWaitingMessage = "OVN %s DB cluster %s not ready yet"
+
+	// OVNDBClusterErrorMessage
+	OVNDBClusterErrorMessage = "OVN %s DB cluster %s error: %s"
+
+	//
+	// Paused condition messages
+	//
+	// OVNNorthdPausedMessage
+	OVNNorthdPausedMessage = "ovn-northd paused"
+
+	// OVNNorthdPausingMessage
+	OVNNorthdPausingMessage = "ovn-northd pausing, waiting for instances: %s"
+
+	// OVNNorthdResumingMessage
+	OVNNorthdResumingMessage = "ovn-northd resuming, waiting for instances: %s"
+
+	//
+	// ConfigLagging condition messages
+	//
+	// OVNConfigLaggingMessage
+	OVNConfigLaggingMessage = "NB configuration not propagated: %s"
+
+	//
+	// OVNTraceCompleted condition messages
+	//
+	// OVNTraceCompletedInitMessage
+	OVNTraceCompletedInitMessage = "OVN trace not started"
+
+	// OVNTraceRunningMessage
+	OVNTraceRunningMessage = "OVN trace in progress"
+
+	// OVNTraceCompletedMessage
+	OVNTraceCompletedMessage = "OVN trace completed, result in ConfigMap %s"
+
+	// OVNTraceErrorMessage
+	OVNTraceErrorMessage = "OVN trace failed: %s"
+)
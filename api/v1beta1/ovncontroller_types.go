@@ -78,6 +78,31 @@ type OVNControllerSpec struct {
 	// If present, the IP of the attachment named "tenant", will be used as the OvnEncapIP.
 
 	NetworkAttachments []string `json:"networkAttachments,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLS - Parameters related to the TLS connection to the SB database
+	TLS OVNControllerTLS `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SBClusterRef - the SB OVNDBCluster to connect to. If not set, the SB OVNDBCluster of the namespace is used
+	SBClusterRef *OVNDBClusterRef `json:"sbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// ConfigLagThreshold - seconds a chassis may stay behind the NB configuration before the ConfigLagging
+	// condition is set
+	ConfigLagThreshold int32 `json:"configLagThreshold"`
+}
+
+// OVNControllerTLS defines the TLS settings of ovn-controller
+type OVNControllerTLS struct {
+	// +kubebuilder:validation:Optional
+	// CASecretName - Secret holding the CA (tls.crt, tls.key) the operator issues the per-chassis client
+	// certificates with. The CN of each certificate is the system-id of the chassis, as required by the SB
+	// RBAC. Only the certificate of its node is mounted on a chassis, the CA key never leaves the operator.
+	// The same CA has to have issued the certificate of the SB ovsdb-server.
+	CASecretName string `json:"caSecretName,omitempty"`
 }
 
 // OVNControllerDebug defines the observed state of OVNControllerDebug
@@ -90,6 +115,10 @@ type OVNControllerDebug struct {
 
 // OVNControllerStatus defines the observed state of OVNController
 type OVNControllerStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller. Together with
+	// the Ready condition it tells whether a spec change has been applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// NumberReady of the OVNController instances
 	NumberReady int32 `json:"numberReady,omitempty"`
 
@@ -104,6 +133,37 @@ type OVNControllerStatus struct {
 
 	// NetworkAttachments status of the deployment pods
 	NetworkAttachments map[string][]string `json:"networkAttachments,omitempty"`
+
+	// ConfigPropagation - how far the chassis got with the NB configuration, read from the SB_Global and
+	// Chassis_Private tables
+	ConfigPropagation *OVNControllerConfigPropagation `json:"configPropagation,omitempty"`
+}
+
+// OVNControllerConfigPropagation defines the NB configuration sequence numbers reached by the chassis
+type OVNControllerConfigPropagation struct {
+	// NBCfg - the NB configuration ovn-northd wrote to the SB database
+	NBCfg int64 `json:"nbCfg"`
+
+	// Chassis - number of chassis registered in the SB database
+	Chassis int32 `json:"chassis"`
+
+	// LaggingChassis - the chassis behind the NB configuration
+	LaggingChassis []OVNChassisLag `json:"laggingChassis,omitempty"`
+
+	// LastCheckTime - when the sequence numbers were read
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+}
+
+// OVNChassisLag defines a chassis behind the NB configuration
+type OVNChassisLag struct {
+	// Name - name of the chassis
+	Name string `json:"name"`
+
+	// NBCfg - the NB configuration the chassis applied
+	NBCfg int64 `json:"nbCfg"`
+
+	// Pending - the NB configuration the chassis has not applied yet
+	Pending OVNConfigLag `json:"pending"`
 }
 
 //+kubebuilder:object:root=true
@@ -141,6 +201,11 @@ func (instance OVNController) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
 }
 
+// IsTLSEnabled - returns true if ovn-controller connects to the SB database with per-chassis certificates
+func (instance OVNController) IsTLSEnabled() bool {
+	return instance.Spec.TLS.CASecretName != ""
+}
+
 // OVSExternalIDs is a set of configuration options for OVS external-ids table
 type OVSExternalIDs struct {
 	// +kubebuilder:validation:Optional
@@ -161,6 +226,11 @@ type OVSExternalIDs struct {
 	EnableChassisAsGateway bool `json:"enable-chassis-as-gateway"`
 }
 
+// ConsumesDBCluster - returns true if ovn-controller connects to the database of the OVNDBCluster
+func (instance OVNController) ConsumesDBCluster(cluster *OVNDBCluster) bool {
+	return cluster.Spec.DBType == SBDBType && instance.Spec.SBClusterRef.RefersTo(cluster, instance.Namespace)
+}
+
 // RbacConditionsSet - set the conditions for the rbac object
 func (instance OVNController) RbacConditionsSet(c *condition.Condition) {
 	instance.Status.Conditions.Set(c)
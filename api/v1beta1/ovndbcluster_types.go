@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 
@@ -37,6 +38,47 @@ const (
 	OvnNBContainerImage = "quay.io/podified-antelope-centos9/openstack-ovn-nb-db-server:current-podified"
 	// OvnSBContainerImage is the fall-back container image for OVNDBCluster SB
 	OvnSBContainerImage = "quay.io/podified-antelope-centos9/openstack-ovn-sb-db-server:current-podified"
+
+	// DBPortNB - Northbound database port
+	DBPortNB = 6641
+	// DBPortSB - Southbound database port
+	DBPortSB = 6642
+	// RaftPortNB - Northbound RAFT port
+	RaftPortNB = 6643
+	// RaftPortSB - Southbound RAFT port
+	RaftPortSB = 6644
+	// DBPortSBChassis - Southbound database port restricted to the ovn-controller RBAC role
+	DBPortSBChassis = 16642
+
+	// RaftMode - the members form a clustered database using the RAFT protocol
+	RaftMode = "raft"
+	// ActiveBackupMode - one standalone active member, the other one replicates it with --sync-from
+	ActiveBackupMode = "active-backup"
+	// ExternalMode - the database is managed outside of the operator, only its endpoint is published
+	ExternalMode = "external"
+
+	// ClusterIPAddressMode - the DB addresses use the ClusterIP of the per-pod Services
+	ClusterIPAddressMode = "ClusterIP"
+	// PodDNSAddressMode - the DB addresses use the DNS names of the pods within the headless Service
+	PodDNSAddressMode = "PodDNS"
+	// ServiceDNSAddressMode - the DB addresses use the DNS names of the per-pod Services
+	ServiceDNSAddressMode = "ServiceDNS"
+
+	// DefaultClusterDomain - fall-back DNS domain of the k8s cluster
+	DefaultClusterDomain = "cluster.local"
+
+	// PersistentStorage - the DB files are stored on a PersistentVolumeClaim per member
+	PersistentStorage = "persistent"
+	// EphemeralStorage - the DB files are stored on an emptyDir and lost when the member restarts
+	EphemeralStorage = "ephemeral"
+
+	// ReplacementPhaseRejoining - the replaced member got kicked out and deleted, its replacement is joining
+	ReplacementPhaseRejoining = "Rejoining"
+	// ReplacementPhaseCompleted - the replacement of the member joined the cluster
+	ReplacementPhaseCompleted = "Completed"
+
+	// ForceDeleteAnnotation - set to "true" to delete an OVNDBCluster still used by OVNNorthd or OVNController CRs
+	ForceDeleteAnnotation = "ovn.openstack.org/force-delete"
 )
 
 // OVNDBClusterSpec defines the desired state of OVNDBCluster
@@ -59,6 +101,19 @@ type OVNDBClusterSpec struct {
 	// Replicas of OVN DBCluster to run
 	Replicas *int32 `json:"replicas"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=raft
+	// +kubebuilder:validation:Enum=raft;active-backup;external
+	// Mode - raft runs a clustered database. active-backup runs a standalone active member and a backup
+	// member replicating it, for deployments that can't afford three members. At most 2 replicas are
+	// supported in active-backup mode. external deploys nothing and publishes the endpoint of a database
+	// managed elsewhere, e.g. by TripleO during a migration.
+	Mode string `json:"mode"`
+
+	// +kubebuilder:validation:Optional
+	// External - external mode only. The database managed outside of the operator
+	External OVNDBClusterExternal `json:"external,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// NodeSelector to target subset of worker nodes running this service
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -98,13 +153,154 @@ type OVNDBClusterSpec struct {
 	StorageClass string `json:"storageClass,omitempty"`
 
 	// +kubebuilder:validation:Required
-	// StorageRequest
+	// StorageRequest - size of the PersistentVolumeClaim, or the size limit of the emptyDir in ephemeral mode
 	StorageRequest string `json:"storageRequest"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=persistent
+	// +kubebuilder:validation:Enum=persistent;ephemeral
+	// StorageMode - ephemeral stores the DB files on an emptyDir, for CI and development clusters without a
+	// storage provisioner. A restarted member always rejoins from scratch and the data is lost once all
+	// members restart.
+	StorageMode string `json:"storageMode"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum="";Memory
+	// StorageMedium - ephemeral storage mode only. Memory backs the emptyDir with tmpfs
+	StorageMedium corev1.StorageMedium `json:"storageMedium,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// NetworkAttachment is a NetworkAttachment resource name to expose the service to the given network.
 	// If specified the IP address of this network is used as the dbAddress connection.
 	NetworkAttachment string `json:"networkAttachment"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;PodDNS;ServiceDNS
+	// AddressMode - how the members are published in the internal DB and RAFT addresses. The DNS based modes
+	// publish stable names which survive the recreation of the per-pod Services.
+	AddressMode string `json:"addressMode"`
+
+	// +kubebuilder:validation:Optional
+	// ClusterDomain - DNS domain of the k8s cluster used for the member names (will be set to the operator
+	// default if empty)
+	ClusterDomain string `json:"clusterDomain"`
+
+	// +kubebuilder:validation:Optional
+	// TLS - Parameters related to the TLS listeners of the ovsdb-server
+	TLS OVNDBClusterTLS `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// EnableRBAC - SB only. Enables OVN role-based access control. ovn-controller clients get a dedicated
+	// listener restricted to the "ovn-controller" RBAC role, and each chassis is identified by the CN of its
+	// client certificate, which has to match its system-id. Requires TLS.
+	EnableRBAC bool `json:"enableRBAC"`
+
+	// +kubebuilder:validation:Optional
+	// IntegrityCheck - periodic verification of the DB files of each member with ovsdb-tool
+	IntegrityCheck OVNDBClusterIntegrityCheck `json:"integrityCheck,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// LeaderService - raft mode only. Creates a Service selecting only the current RAFT leader, via the
+	// ovn-role=leader label the operator keeps on the members. Clients connecting to it save the round-trips
+	// of a connection to a follower. Its address is published as internalLeaderDbAddress.
+	LeaderService bool `json:"leaderService"`
+
+	// +kubebuilder:validation:Optional
+	// ReplaceMembers - raft mode only. Pods of the members to replace, e.g. ovsdbserver-sb-2 after losing its
+	// PVC or node. The operator kicks the server of the member out of the cluster, deletes its PVC and pod,
+	// and waits for the new member to join. A member is replaced once, remove it from the list to replace it
+	// again later.
+	ReplaceMembers []string `json:"replaceMembers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AutoHeal - raft mode with persistent storage only. Automatic rebuild of members crashlooping because
+	// of corrupt DB files
+	AutoHeal OVNDBClusterAutoHeal `json:"autoHeal,omitempty"`
+}
+
+// OVNDBClusterIntegrityCheck defines the periodic integrity check of the members DB files
+type OVNDBClusterIntegrityCheck struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - run the integrity check job against a copy of each member's DB files
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1440
+	// +kubebuilder:validation:Minimum=5
+	// Interval - minutes between two integrity checks of a member
+	Interval int32 `json:"interval"`
+}
+
+// OVNDBClusterAutoHeal defines the automatic rebuild of members with corrupt DB files
+type OVNDBClusterAutoHeal struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - check the DB files of a member once it restarted restartThreshold times, and rebuild it from
+	// the cluster like the members in replaceMembers if they fail ovsdb-tool check-cluster. A member is
+	// only rebuilt while the other members have a leader.
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// RestartThreshold - restarts of a crashlooping member before its DB files get checked
+	RestartThreshold int32 `json:"restartThreshold"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=5
+	// MinInterval - minimum minutes between two automatic rebuilds
+	MinInterval int32 `json:"minInterval"`
+}
+
+// OVNDBClusterExternal defines the database managed outside of the operator
+type OVNDBClusterExternal struct {
+	// +kubebuilder:validation:Optional
+	// Endpoint - comma separated OVSDB remotes of the external database, e.g.
+	// ssl:192.168.24.1:6641,ssl:192.168.24.2:6641. With ssl remotes tls.secretName has to hold the ca.crt
+	// verifying the external ovsdb-server, and optionally the tls.crt and tls.key of a client certificate.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// OVNDBClusterRef - reference to an OVNDBCluster, possibly in another namespace
+type OVNDBClusterRef struct {
+	// +kubebuilder:validation:Required
+	// Name - name of the OVNDBCluster
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// Namespace - namespace of the OVNDBCluster, defaults to the namespace of the referencing CR
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetNamespace - returns the namespace of the referenced OVNDBCluster
+func (ref OVNDBClusterRef) GetNamespace(namespace string) string {
+	if ref.Namespace == "" {
+		return namespace
+	}
+	return ref.Namespace
+}
+
+// RefersTo - returns true if a CR in the namespace using the reference connects to the OVNDBCluster.
+// Without reference the CR uses the OVNDBClusters of its own namespace.
+func (ref *OVNDBClusterRef) RefersTo(cluster *OVNDBCluster, namespace string) bool {
+	if ref == nil {
+		return cluster.Namespace == namespace
+	}
+	return ref.Name == cluster.Name && ref.GetNamespace(namespace) == cluster.Namespace
+}
+
+// OVNDBClusterTLS defines the TLS settings of the ovsdb-server
+type OVNDBClusterTLS struct {
+	// +kubebuilder:validation:Optional
+	// SecretName - Secret holding tls.crt, tls.key and ca.crt used by the DB and RAFT listeners.
+	// Clients are required to present a certificate signed by ca.crt. In external mode the Secret holds
+	// the ca.crt, and optionally a client tls.crt and tls.key, used to connect to the external database.
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // OVNDBclusterDebug defines the observed state of OVNDBClusterDebug
@@ -117,6 +313,10 @@ type OVNDBClusterDebug struct {
 
 // OVNDBClusterStatus defines the observed state of OVNDBCluster
 type OVNDBClusterStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller. Together with
+	// the Ready condition it tells whether a spec change has been applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// ReadyCount of OVN DBCluster instances
 	ReadyCount int32 `json:"readyCount,omitempty"`
 
@@ -129,14 +329,87 @@ type OVNDBClusterStatus struct {
 	// RaftAddress -
 	RaftAddress string `json:"raftAddress,omitempty"`
 
+	// ActiveMember - active-backup mode only. The member serving the published DB addresses
+	ActiveMember string `json:"activeMember,omitempty"`
+
+	// Leader - raft mode only. The member currently leading the RAFT cluster
+	Leader string `json:"leader,omitempty"`
+
+	// Members - raft mode only. RAFT addresses of the servers of the cluster as known by the leader
+	Members []string `json:"members,omitempty"`
+
+	// ElectionTimer - raft mode only. Election timer of the running cluster in milliseconds
+	ElectionTimer int32 `json:"electionTimer,omitempty"`
+
 	// DBAddress - DB IP address used by external nodes
 	DBAddress string `json:"dbAddress,omitempty"`
 
 	// InternalDBAddress - DB IP address used by other Pods in the cluster
 	InternalDBAddress string `json:"internalDbAddress,omitempty"`
 
+	// InternalLeaderDBAddress - raft mode with leaderService only. DB address of the Service selecting the
+	// RAFT leader, used by other Pods in the cluster
+	InternalLeaderDBAddress string `json:"internalLeaderDbAddress,omitempty"`
+
+	// ChassisDBAddress - DB IP address used by external chassis, restricted to the ovn-controller RBAC role
+	ChassisDBAddress string `json:"chassisDbAddress,omitempty"`
+
+	// InternalChassisDBAddress - DB IP address used by ovn-controller Pods in the cluster, restricted to
+	// the ovn-controller RBAC role
+	InternalChassisDBAddress string `json:"internalChassisDbAddress,omitempty"`
+
 	// NetworkAttachments status of the deployment pods
 	NetworkAttachments map[string][]string `json:"networkAttachments,omitempty"`
+
+	// EphemeralStorage - true if the NB/SB data is not persistent, it is lost once all members restart
+	EphemeralStorage bool `json:"ephemeralStorage,omitempty"`
+
+	// IntegrityCheck - result of the last integrity check per member
+	IntegrityCheck map[string]OVNDBMemberIntegrity `json:"integrityCheck,omitempty"`
+	// Replacements - progress of the replacement of the members listed in replaceMembers
+	Replacements map[string]OVNDBMemberReplacement `json:"replacements,omitempty"`
+
+	// AutoHeals - the last members rebuilt by the automatic healing, most recent last
+	AutoHeals []OVNDBMemberHeal `json:"autoHeals,omitempty"`
+}
+
+// OVNDBMemberIntegrity defines the result of the integrity check of a member
+type OVNDBMemberIntegrity struct {
+	// LastCheckTime - time the last integrity check of the member finished
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Degraded - true if the last integrity check of the member failed
+	Degraded bool `json:"degraded"`
+}
+
+// OVNDBMemberReplacement defines the progress of the replacement of a member
+type OVNDBMemberReplacement struct {
+	// Phase - Rejoining once the member got kicked out and deleted, Completed once its replacement joined
+	Phase string `json:"phase"`
+
+	// ServerID - server ID of the replaced member
+	ServerID string `json:"serverID,omitempty"`
+
+	// PodUID - UID of the replaced pod
+	PodUID string `json:"podUID,omitempty"`
+
+	// Automatic - true if the member got replaced by the automatic healing
+	Automatic bool `json:"automatic,omitempty"`
+}
+
+// OVNDBMemberHeal defines an automatic rebuild of a member
+type OVNDBMemberHeal struct {
+	// Member - pod of the rebuilt member
+	Member string `json:"member"`
+
+	// ServerID - server ID of the rebuilt member which got kicked out of the cluster
+	ServerID string `json:"serverID,omitempty"`
+
+	// Restarts - restarts of the member before it got rebuilt
+	Restarts int32 `json:"restarts"`
+
+	// Time - time the member got rebuilt
+	Time metav1.Time `json:"time"`
 }
 
 //+kubebuilder:object:root=true
@@ -202,3 +475,86 @@ func (instance OVNDBCluster) GetExternalEndpoint() (string, error) {
 	}
 	return instance.Status.DBAddress, nil
 }
+
+// GetInternalChassisEndpoint - returns the endpoint ovn-controller Pods should connect to. It is the
+// RBAC restricted listener when RBAC is enabled and the regular internal endpoint otherwise.
+func (instance OVNDBCluster) GetInternalChassisEndpoint() (string, error) {
+	if !instance.Spec.EnableRBAC {
+		return instance.GetInternalEndpoint()
+	}
+	if instance.Status.InternalChassisDBAddress == "" {
+		return "", fmt.Errorf("internal chassis DBEndpoint not ready yet for %s", instance.Spec.DBType)
+	}
+	return instance.Status.InternalChassisDBAddress, nil
+}
+
+// GetExternalChassisEndpoint - returns the endpoint external chassis should connect to. It is the
+// RBAC restricted listener when RBAC is enabled and the regular external endpoint otherwise.
+func (instance OVNDBCluster) GetExternalChassisEndpoint() (string, error) {
+	if !instance.Spec.EnableRBAC {
+		return instance.GetExternalEndpoint()
+	}
+	if instance.Spec.NetworkAttachment != "" && instance.Status.ChassisDBAddress == "" {
+		return "", fmt.Errorf("external chassis DBEndpoint not ready yet for %s", instance.Spec.DBType)
+	}
+	return instance.Status.ChassisDBAddress, nil
+}
+
+// IsForceDelete - returns true if the deletion must not wait for the dependent CRs to be gone
+func (instance OVNDBCluster) IsForceDelete() bool {
+	return instance.Annotations[ForceDeleteAnnotation] == "true"
+}
+
+// GetClusterDomain - returns the DNS domain of the k8s cluster
+func (instance OVNDBCluster) GetClusterDomain() string {
+	if instance.Spec.ClusterDomain == "" {
+		return DefaultClusterDomain
+	}
+	return instance.Spec.ClusterDomain
+}
+
+// IsEphemeral - returns true if the DB files are not persisted
+func (instance OVNDBCluster) IsEphemeral() bool {
+	return instance.Spec.StorageMode == EphemeralStorage
+}
+
+// IsActiveBackup - returns true if the members run in active-backup mode
+func (instance OVNDBCluster) IsActiveBackup() bool {
+	return instance.Spec.Mode == ActiveBackupMode
+}
+
+// IsExternal - returns true if the database is managed outside of the operator
+func (instance OVNDBCluster) IsExternal() bool {
+	return instance.Spec.Mode == ExternalMode
+}
+
+// GetExternalRemotes - returns the OVSDB remotes of the external database
+func (instance OVNDBCluster) GetExternalRemotes() []string {
+	remotes := []string{}
+	for _, remote := range strings.Split(instance.Spec.External.Endpoint, ",") {
+		if remote = strings.TrimSpace(remote); remote != "" {
+			remotes = append(remotes, remote)
+		}
+	}
+	return remotes
+}
+
+// GetInternalRemotes - returns the OVSDB remotes of the internal endpoint
+func (instance OVNDBCluster) GetInternalRemotes() ([]string, error) {
+	endpoint, err := instance.GetInternalEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	remotes := []string{}
+	for _, remote := range strings.Split(endpoint, ",") {
+		if remote = strings.TrimSpace(remote); remote != "" {
+			remotes = append(remotes, remote)
+		}
+	}
+	return remotes, nil
+}
+
+// IsTLSEnabled - returns true if the ovsdb-server listeners use TLS
+func (instance OVNDBCluster) IsTLSEnabled() bool {
+	return instance.Spec.TLS.SecretName != ""
+}
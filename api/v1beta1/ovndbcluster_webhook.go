@@ -23,8 +23,17 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -33,6 +42,7 @@ import (
 type OVNDBClusterDefaults struct {
 	NBContainerImageURL string
 	SBContainerImageURL string
+	ClusterDomain       string
 }
 
 var ovnDbClusterDefaults OVNDBClusterDefaults
@@ -40,6 +50,12 @@ var ovnDbClusterDefaults OVNDBClusterDefaults
 // log is for logging in this package.
 var ovndbclusterlog = logf.Log.WithName("ovndbcluster-resource")
 
+// ovsdbRemoteRegex matches an active OVSDB remote, the host being a name, an IPv4 or a bracketed IPv6 address
+var ovsdbRemoteRegex = regexp.MustCompile(`^(tcp|ssl):([^:\[\]]+|\[[0-9a-fA-F:.]+\]):[0-9]+$`)
+
+// ovndbclusterClient is used to look up the dependents of an OVNDBCluster on delete and on enabling TLS
+var ovndbclusterClient client.Client
+
 // SetupOVNDBClusterDefaults - initialize OVNDBCluster spec defaults for use with either internal or external webhooks
 func SetupOVNDBClusterDefaults(defaults OVNDBClusterDefaults) {
 	ovnDbClusterDefaults = defaults
@@ -48,6 +64,7 @@ func SetupOVNDBClusterDefaults(defaults OVNDBClusterDefaults) {
 
 // SetupWebhookWithManager sets up the webhook with the Manager
 func (r *OVNDBCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	ovndbclusterClient = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -73,10 +90,12 @@ func (spec *OVNDBClusterSpec) Default() {
 			spec.ContainerImage = ovnDbClusterDefaults.SBContainerImageURL
 		}
 	}
+	if spec.ClusterDomain == "" {
+		spec.ClusterDomain = ovnDbClusterDefaults.ClusterDomain
+	}
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-//+kubebuilder:webhook:path=/validate-ovn-openstack-org-v1beta1-ovndbcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=ovn.openstack.org,resources=ovndbclusters,verbs=create;update,versions=v1beta1,name=vovndbcluster.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-ovn-openstack-org-v1beta1-ovndbcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=ovn.openstack.org,resources=ovndbclusters,verbs=create;update;delete,versions=v1beta1,name=vovndbcluster.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &OVNDBCluster{}
 
@@ -84,22 +103,231 @@ var _ webhook.Validator = &OVNDBCluster{}
 func (r *OVNDBCluster) ValidateCreate() error {
 	ovndbclusterlog.Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return r.validateTLSDependents()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *OVNDBCluster) ValidateUpdate(old runtime.Object) error {
 	ovndbclusterlog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	oldInstance, ok := old.(*OVNDBCluster)
+	if ok {
+		var allErrs field.ErrorList
+		basePath := field.NewPath("spec")
+		if oldInstance.Spec.Mode != r.Spec.Mode {
+			allErrs = append(allErrs, field.Forbidden(
+				basePath.Child("mode"), "the DB files can't be converted, mode is immutable"))
+		}
+		if oldInstance.Spec.ClusterDomain != "" && oldInstance.Spec.ClusterDomain != r.Spec.ClusterDomain {
+			allErrs = append(allErrs, field.Forbidden(
+				basePath.Child("clusterDomain"), "the members are known by their DNS names, clusterDomain is immutable"))
+		}
+		if oldInstance.Spec.StorageMode != r.Spec.StorageMode {
+			allErrs = append(allErrs, field.Forbidden(
+				basePath.Child("storageMode"), "the DB files can't be moved, storageMode is immutable"))
+		}
+		if len(allErrs) != 0 {
+			return apierrors.NewInvalid(
+				schema.GroupKind{Group: GroupVersion.Group, Kind: "OVNDBCluster"},
+				r.Name, allErrs)
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return err
+	}
+	if ok && oldInstance.servesSSL() {
+		return nil
+	}
+	return r.validateTLSDependents()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *OVNDBCluster) ValidateDelete() error {
 	ovndbclusterlog.Info("validate delete", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object deletion.
+	if ovndbclusterClient == nil || r.IsForceDelete() {
+		return nil
+	}
+	dependents, err := GetDBClusterDependents(context.TODO(), ovndbclusterClient, r)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return apierrors.NewForbidden(
+			schema.GroupResource{Group: GroupVersion.Group, Resource: "ovndbclusters"}, r.Name,
+			fmt.Errorf("still used by %s, delete them first or set the %s=true annotation",
+				strings.Join(dependents, ", "), ForceDeleteAnnotation))
+	}
+	return nil
+}
+
+// validateTLSDependents - rejects serving SSL, or publishing an external database serving SSL, while OVNNorthds
+// or OVNControllers connecting to the database have no client certificate, they could not connect anymore
+func (r *OVNDBCluster) validateTLSDependents() error {
+	if ovndbclusterClient == nil || !r.servesSSL() {
+		return nil
+	}
+	dependents, err := GetDBClusterDependentsWithoutTLS(context.TODO(), ovndbclusterClient, r)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "OVNDBCluster"},
+			r.Name, field.ErrorList{field.Forbidden(
+				field.NewPath("spec").Child("tls").Child("secretName"),
+				fmt.Sprintf("%s have no client certificate, set their tls first", strings.Join(dependents, ", ")))})
+	}
 	return nil
 }
+
+// servesSSL - true if the clients connect to the database with SSL, the TLS Secret of an external database
+// only matters to its ssl remotes
+func (r *OVNDBCluster) servesSSL() bool {
+	if r.Spec.TLS.SecretName == "" {
+		return false
+	}
+	if r.Spec.Mode != ExternalMode {
+		return true
+	}
+	for _, remote := range r.GetExternalRemotes() {
+		if strings.HasPrefix(remote, "ssl:") {
+			return true
+		}
+	}
+	return false
+}
+
+// validate - checks the cross field constraints of the OVNDBCluster spec
+func (r *OVNDBCluster) validate() error {
+	var allErrs field.ErrorList
+	basePath := field.NewPath("spec")
+
+	if r.Spec.EnableRBAC {
+		if r.Spec.DBType != SBDBType {
+			allErrs = append(allErrs, field.Invalid(
+				basePath.Child("enableRBAC"), r.Spec.EnableRBAC, "RBAC is only supported for the SB database"))
+		}
+		if r.Spec.TLS.SecretName == "" {
+			allErrs = append(allErrs, field.Required(
+				basePath.Child("tls").Child("secretName"), "RBAC requires TLS to identify the chassis"))
+		}
+	}
+
+	if r.Spec.Mode == ActiveBackupMode && r.Spec.Replicas != nil && *r.Spec.Replicas > 2 {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("replicas"), *r.Spec.Replicas, "active-backup mode supports at most 2 replicas"))
+	}
+
+	if r.Spec.Mode == ExternalMode {
+		allErrs = append(allErrs, r.validateExternal(basePath)...)
+	} else if r.Spec.External.Endpoint != "" {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("external").Child("endpoint"), r.Spec.External.Endpoint,
+			"the endpoint is only used in external mode"))
+	}
+
+	if r.Spec.LeaderService && (r.Spec.Mode == ActiveBackupMode || r.Spec.Mode == ExternalMode) {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("leaderService"), r.Spec.LeaderService, "the leader Service requires raft mode"))
+	}
+
+	allErrs = append(allErrs, r.validateReplaceMembers(basePath)...)
+
+	if r.Spec.AutoHeal.Enabled {
+		autoHealPath := basePath.Child("autoHeal").Child("enabled")
+		if r.Spec.Mode == ActiveBackupMode || r.Spec.Mode == ExternalMode {
+			allErrs = append(allErrs, field.Invalid(
+				autoHealPath, r.Spec.AutoHeal.Enabled, "automatic healing requires raft mode"))
+		} else if r.Spec.StorageMode == EphemeralStorage {
+			allErrs = append(allErrs, field.Invalid(
+				autoHealPath, r.Spec.AutoHeal.Enabled, "automatic healing requires persistent storage"))
+		}
+	}
+
+	if r.Spec.StorageMode == EphemeralStorage && r.Spec.IntegrityCheck.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("integrityCheck").Child("enabled"), r.Spec.IntegrityCheck.Enabled,
+			"the integrity check requires persistent storage"))
+	}
+	if r.Spec.StorageMode != EphemeralStorage && r.Spec.StorageMedium != "" {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("storageMedium"), r.Spec.StorageMedium, "storageMedium requires ephemeral storage"))
+	}
+
+	if len(allErrs) != 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "OVNDBCluster"},
+			r.Name, allErrs)
+	}
+	return nil
+}
+
+// validateExternal - checks the external database of an external mode OVNDBCluster
+func (r *OVNDBCluster) validateExternal(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	endpointPath := basePath.Child("external").Child("endpoint")
+
+	remotes := r.GetExternalRemotes()
+	if len(remotes) == 0 {
+		allErrs = append(allErrs, field.Required(endpointPath, "external mode requires the endpoint of the database"))
+	}
+	ssl := false
+	for _, remote := range remotes {
+		if !ovsdbRemoteRegex.MatchString(remote) {
+			allErrs = append(allErrs, field.Invalid(
+				endpointPath, remote, "remotes have to be of the form tcp:<host>:<port> or ssl:<host>:<port>"))
+		}
+		ssl = ssl || strings.HasPrefix(remote, "ssl:")
+	}
+	if ssl && r.Spec.TLS.SecretName == "" {
+		allErrs = append(allErrs, field.Required(
+			basePath.Child("tls").Child("secretName"), "ssl remotes require the CA of the external database"))
+	}
+	if r.Spec.EnableRBAC {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("enableRBAC"), r.Spec.EnableRBAC, "RBAC is not supported in external mode"))
+	}
+	if r.Spec.IntegrityCheck.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("integrityCheck").Child("enabled"), r.Spec.IntegrityCheck.Enabled,
+			"the integrity check is not supported in external mode"))
+	}
+	return allErrs
+}
+
+// validateReplaceMembers - checks the members to replace are members of a raft cluster keeping its quorum
+// while one of them is replaced
+func (r *OVNDBCluster) validateReplaceMembers(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(r.Spec.ReplaceMembers) == 0 {
+		return allErrs
+	}
+	path := basePath.Child("replaceMembers")
+
+	if r.Spec.Mode == ActiveBackupMode || r.Spec.Mode == ExternalMode {
+		return append(allErrs, field.Invalid(path, r.Spec.ReplaceMembers, "replacing members requires raft mode"))
+	}
+	if r.Spec.Replicas == nil || *r.Spec.Replicas < 3 {
+		return append(allErrs, field.Invalid(path, r.Spec.ReplaceMembers,
+			"replacing members requires at least 3 replicas to keep the quorum"))
+	}
+	members := []string{}
+	for i := 0; i < int(*r.Spec.Replicas); i++ {
+		members = append(members, fmt.Sprintf("ovsdbserver-%s-%d", strings.ToLower(r.Spec.DBType), i))
+	}
+	for i, member := range r.Spec.ReplaceMembers {
+		found := false
+		for _, m := range members {
+			found = found || m == member
+		}
+		if !found {
+			allErrs = append(allErrs, field.NotSupported(path.Index(i), member, members))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MigrationPhasePending - waiting for the inputs of the migration
+	MigrationPhasePending = "Pending"
+	// MigrationPhaseMigrating - the initial copy of the data is running
+	MigrationPhaseMigrating = "Migrating"
+	// MigrationPhaseSyncing - the data got copied, it is copied again periodically until cutover
+	MigrationPhaseSyncing = "Syncing"
+	// MigrationPhaseCompleted - the final copy of the data finished
+	MigrationPhaseCompleted = "Completed"
+	// MigrationPhaseFailed - a copy of the data failed
+	MigrationPhaseFailed = "Failed"
+)
+
+// OVNDBMigrationSpec defines the desired state of OVNDBMigration
+type OVNDBMigrationSpec struct {
+	// +kubebuilder:validation:Required
+	// TargetCluster - name of the OVNDBCluster, in the same namespace, the data is copied into. Its content
+	// gets replaced.
+	TargetCluster string `json:"targetCluster"`
+
+	// +kubebuilder:validation:Required
+	// Source - the OVSDB server the data is copied from
+	Source OVNDBMigrationSource `json:"source"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerImage - Container Image URL providing ovsdb-client (will be set to the image of the target
+	// OVNDBCluster if empty)
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// ContinuousSync - keep copying the data periodically until cutover is set
+	ContinuousSync bool `json:"continuousSync"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=10
+	// SyncInterval - seconds between two copies of the data in continuous sync
+	SyncInterval int32 `json:"syncInterval"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Cutover - continuous sync only. Runs a last copy of the data and completes the migration. The source
+	// is expected to not take writes anymore.
+	Cutover bool `json:"cutover"`
+}
+
+// OVNDBMigrationSource defines the OVSDB server the data is copied from
+type OVNDBMigrationSource struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(tcp|ssl):([^:\[\]]+|\[[0-9a-fA-F:.]+\]):[0-9]+$`
+	// Endpoint - OVSDB remote of the source database, e.g. ssl:192.168.24.1:6641
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:Optional
+	// TLSSecretName - ssl endpoint only. Secret holding the ca.crt verifying the source ovsdb-server and
+	// the tls.crt and tls.key of a client certificate accepted by it
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
+
+// OVNDBMigrationStatus defines the observed state of OVNDBMigration
+type OVNDBMigrationStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase - Pending, Migrating, Syncing, Completed or Failed
+	Phase string `json:"phase,omitempty"`
+
+	// Syncs - number of copies of the data finished
+	Syncs int32 `json:"syncs,omitempty"`
+
+	// LastSyncTime - time the last copy of the data finished
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// RowCounts - rows per table of the target database after the last copy
+	RowCounts map[string]int64 `json:"rowCounts,omitempty"`
+
+	// Checksum - sha256 of the dump of the target database after the last copy. Compare it with
+	// `ovsdb-client dump -f csv <source> | sha256sum` to verify the cutover.
+	Checksum string `json:"checksum,omitempty"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase"
+//+kubebuilder:printcolumn:name="Syncs",type="integer",JSONPath=".status.syncs",description="Syncs"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OVNDBMigration is the Schema for the ovndbmigrations API
+type OVNDBMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNDBMigrationSpec   `json:"spec,omitempty"`
+	Status OVNDBMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OVNDBMigrationList contains a list of OVNDBMigration
+type OVNDBMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNDBMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OVNDBMigration{}, &OVNDBMigrationList{})
+}
+
+// IsReady - returns true if the migration completed
+func (instance OVNDBMigration) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+// IsFinalSync - returns true if the next copy of the data completes the migration
+func (instance OVNDBMigration) IsFinalSync() bool {
+	return !instance.Spec.ContinuousSync || instance.Spec.Cutover
+}
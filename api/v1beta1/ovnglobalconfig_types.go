@@ -0,0 +1,117 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OVNGlobalConfigSpec defines the desired state of OVNGlobalConfig
+type OVNGlobalConfigSpec struct {
+	// +kubebuilder:validation:Optional
+	// NBClusterRef - the NB OVNDBCluster to configure. If not set, the NB OVNDBCluster of the namespace is used
+	NBClusterRef *OVNDBClusterRef `json:"nbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SBClusterRef - the SB OVNDBCluster to configure. If not set, the SB OVNDBCluster of the namespace is used
+	SBClusterRef *OVNDBClusterRef `json:"sbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Options - options of the NB_Global and SB_Global tables, e.g. mac_prefix, svc_monitor_mac or
+	// use_logical_dp_groups. ovn-northd rebuilds the SB_Global options from the NB_Global ones, so both tables
+	// get the same options. Options removed from the list are removed from the databases, options never listed
+	// are left as they are. Options listed here, e.g. northd_probe_interval, take precedence over the tuning
+	// of the OVNNorthd.
+	Options map[string]string `json:"options,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// IPsec - encrypt the tunnels between the chassis, set in NB_Global and SB_Global. If not set the values
+	// in the databases are left as they are.
+	IPsec *bool `json:"ipsec,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=10
+	// ResyncInterval - seconds between two checks of the databases for values changed outside of the
+	// OVNGlobalConfig
+	ResyncInterval int32 `json:"resyncInterval"`
+}
+
+// OVNGlobalConfigStatus defines the observed state of OVNGlobalConfig
+type OVNGlobalConfigStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ManagedOptions - the NB_Global and SB_Global options set from the spec, removed from the databases once
+	// they are dropped from the spec
+	ManagedOptions []string `json:"managedOptions,omitempty"`
+
+	// Drift - the values found changed outside of the OVNGlobalConfig by the last check which found any,
+	// and restored since, e.g. SB_Global options:mac_prefix
+	Drift []string `json:"drift,omitempty"`
+
+	// LastDriftTime - time the last drift got restored
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+
+	// Map of hashes to track e.g. the applied spec
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OVNGlobalConfig is the Schema for the ovnglobalconfigs API
+type OVNGlobalConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNGlobalConfigSpec   `json:"spec,omitempty"`
+	Status OVNGlobalConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OVNGlobalConfigList contains a list of OVNGlobalConfig
+type OVNGlobalConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNGlobalConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OVNGlobalConfig{}, &OVNGlobalConfigList{})
+}
+
+// IsReady - returns true if the configuration is applied
+func (instance OVNGlobalConfig) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+// ConsumesDBCluster - returns true if the OVNGlobalConfig configures the database of the OVNDBCluster
+func (instance OVNGlobalConfig) ConsumesDBCluster(cluster *OVNDBCluster) bool {
+	if cluster.Spec.DBType == NBDBType {
+		return instance.Spec.NBClusterRef.RefersTo(cluster, instance.Namespace)
+	}
+	return instance.Spec.SBClusterRef.RefersTo(cluster, instance.Namespace)
+}
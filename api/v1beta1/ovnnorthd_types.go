@@ -66,6 +66,96 @@ type OVNNorthdSpec struct {
 	// NetworkAttachment is a NetworkAttachment resource name to expose the service to the given network.
 	// If specified the IP address of this network is used as the dbAddress connection.
 	NetworkAttachment string `json:"networkAttachment"`
+
+	// +kubebuilder:validation:Optional
+	// NBClusterRef - the NB OVNDBCluster to connect to. If not set, the NB OVNDBCluster of the namespace is used
+	NBClusterRef *OVNDBClusterRef `json:"nbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SBClusterRef - the SB OVNDBCluster to connect to. If not set, the SB OVNDBCluster of the namespace is used
+	SBClusterRef *OVNDBClusterRef `json:"sbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=10
+	// ConnectionTimeout - seconds an ovn-northd instance may stay disconnected from the NB or SB database
+	// before it gets restarted. A disconnected instance is not ready.
+	ConnectionTimeout int32 `json:"connectionTimeout"`
+
+	// +kubebuilder:validation:Optional
+	// TLS - Parameters related to the TLS connections to the NB and SB databases
+	TLS OVNNorthdTLS `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tuning - ovn-northd settings for large deployments
+	Tuning OVNNorthdTuning `json:"tuning,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Metrics - exporter of the ovn-northd engine statistics
+	Metrics OVNNorthdMetrics `json:"metrics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Paused - stops the ovn-northd instances from processing the NB database and writing the SB database,
+	// e.g. during database maintenance. It is applied to the running instances without a restart, instances
+	// starting while paused are paused as well.
+	Paused bool `json:"paused,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// ConfigLagThreshold - seconds the SB database or the chassis may stay behind the NB configuration before
+	// the ConfigLagging condition is set
+	ConfigLagThreshold int32 `json:"configLagThreshold"`
+}
+
+// OVNNorthdMetrics defines the metrics exporter sidecar of ovn-northd
+type OVNNorthdMetrics struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Enabled - run an exporter next to each ovn-northd instance publishing the loop durations of
+	// stopwatch/show and the recompute counts of inc-engine/show-stats as Prometheus metrics, and create a
+	// ServiceMonitor scraping them if the Prometheus operator is installed
+	Enabled bool `json:"enabled"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerImage - image of the exporter, it needs ovn-appctl and python3. If not set the ovn-northd
+	// image is used.
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=9476
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// Port - port the metrics are served on
+	Port int32 `json:"port,omitempty"`
+}
+
+// OVNNorthdTuning defines the performance settings of ovn-northd
+type OVNNorthdTuning struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=256
+	// NThreads - threads building the logical flows in parallel. The CPU request in resources, if set, has to
+	// cover them.
+	NThreads int32 `json:"nThreads,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// ProbeInterval - milliseconds of inactivity before ovn-northd probes its NB and SB connections, 0 disables
+	// the probes. It is kept in the northd_probe_interval option of the NB_Global table, unless an
+	// OVNGlobalConfig of the NB OVNDBCluster sets that option. If not set the value in the database is left
+	// as is.
+	ProbeInterval *int32 `json:"probeInterval,omitempty"`
+}
+
+// OVNNorthdTLS defines the TLS settings of ovn-northd
+type OVNNorthdTLS struct {
+	// +kubebuilder:validation:Optional
+	// SecretName - Secret holding the tls.crt and tls.key of the client certificate used to connect to DB
+	// clusters serving SSL, and the ca.crt verifying their ovsdb-servers. A change of the content restarts
+	// ovn-northd.
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // OVNNorthdDebug defines the observed state of NeutronAPIDebug
@@ -78,6 +168,10 @@ type OVNNorthdDebug struct {
 
 // OVNNorthdStatus defines the observed state of OVNNorthd
 type OVNNorthdStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller. Together with
+	// the Ready condition it tells whether a spec change has been applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// ReadyCount of OVN Northd instances
 	ReadyCount int32 `json:"readyCount,omitempty"`
 
@@ -86,11 +180,65 @@ type OVNNorthdStatus struct {
 
 	// NetworkAttachments status of the deployment pods
 	NetworkAttachments map[string][]string `json:"networkAttachments,omitempty"`
+
+	// ActiveInstance - the ovn-northd pod holding the SB lock, the other replicas are standby
+	ActiveInstance string `json:"activeInstance,omitempty"`
+
+	// Instances - the role of each ready ovn-northd pod
+	Instances []OVNNorthdInstance `json:"instances,omitempty"`
+
+	// Paused - all the running ovn-northd instances are paused
+	Paused bool `json:"paused,omitempty"`
+
+	// ConfigPropagation - how far the NB configuration got, read from the NB_Global table
+	ConfigPropagation *OVNNorthdConfigPropagation `json:"configPropagation,omitempty"`
+}
+
+// OVNNorthdConfigPropagation defines the NB configuration sequence numbers reached by ovn-northd and the
+// chassis. The sequence number is bumped by the clients waiting for their changes, e.g. ovn-nbctl --wait.
+type OVNNorthdConfigPropagation struct {
+	// NBCfg - the requested NB configuration
+	NBCfg int64 `json:"nbCfg"`
+
+	// SBCfg - the NB configuration ovn-northd wrote to the SB database
+	SBCfg int64 `json:"sbCfg"`
+
+	// HVCfg - the NB configuration all the chassis applied
+	HVCfg int64 `json:"hvCfg"`
+
+	// SBLag - set while the SB database is behind the NB configuration
+	SBLag *OVNConfigLag `json:"sbLag,omitempty"`
+
+	// HVLag - set while some chassis are behind the NB configuration
+	HVLag *OVNConfigLag `json:"hvLag,omitempty"`
+
+	// LastCheckTime - when the sequence numbers were read
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+}
+
+// OVNConfigLag defines a NB configuration not reached yet
+type OVNConfigLag struct {
+	// NBCfg - the oldest NB configuration not reached. Newer ones keep the lag going as long as this one is
+	// not reached.
+	NBCfg int64 `json:"nbCfg"`
+
+	// Since - when the NB configuration was first seen not reached
+	Since metav1.Time `json:"since"`
+}
+
+// OVNNorthdInstance defines the role of an ovn-northd pod
+type OVNNorthdInstance struct {
+	// Name - name of the pod
+	Name string `json:"name"`
+
+	// Role - active, standby or paused as reported by ovn-northd, unknown if it can't be queried
+	Role string `json:"role"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="NetworkAttachments",type="string",JSONPath=".status.networkAttachments",description="NetworkAttachments"
+//+kubebuilder:printcolumn:name="Active",type="string",JSONPath=".status.activeInstance",description="Active ovn-northd instance"
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
 //+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
 
@@ -123,6 +271,14 @@ func (instance OVNNorthd) IsReady() bool {
 	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
 }
 
+// ConsumesDBCluster - returns true if ovn-northd connects to the database of the OVNDBCluster
+func (instance OVNNorthd) ConsumesDBCluster(cluster *OVNDBCluster) bool {
+	if cluster.Spec.DBType == NBDBType {
+		return instance.Spec.NBClusterRef.RefersTo(cluster, instance.Namespace)
+	}
+	return instance.Spec.SBClusterRef.RefersTo(cluster, instance.Namespace)
+}
+
 // RbacConditionsSet - set the conditions for the rbac object
 func (instance OVNNorthd) RbacConditionsSet(c *condition.Condition) {
 	instance.Status.Conditions.Set(c)
@@ -137,3 +293,16 @@ func (instance OVNNorthd) RbacNamespace() string {
 func (instance OVNNorthd) RbacResourceName() string {
 	return "ovnnorthd-" + instance.Name
 }
+
+// GetMetricsContainerImage - returns the image of the metrics exporter
+func (instance OVNNorthd) GetMetricsContainerImage() string {
+	if instance.Spec.Metrics.ContainerImage != "" {
+		return instance.Spec.Metrics.ContainerImage
+	}
+	return instance.Spec.ContainerImage
+}
+
+// IsTLSEnabled - returns true if ovn-northd has a client certificate to connect to DB clusters serving SSL
+func (instance OVNNorthd) IsTLSEnabled() bool {
+	return instance.Spec.TLS.SecretName != ""
+}
@@ -23,7 +23,12 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -79,16 +84,14 @@ var _ webhook.Validator = &OVNNorthd{}
 func (r *OVNNorthd) ValidateCreate() error {
 	ovnnorthdlog.Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	return r.validate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *OVNNorthd) ValidateUpdate(old runtime.Object) error {
 	ovnnorthdlog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	return r.validate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -98,3 +101,27 @@ func (r *OVNNorthd) ValidateDelete() error {
 	// TODO(user): fill in your validation logic upon object deletion.
 	return nil
 }
+
+// validate - checks the cross field constraints of the OVNNorthd spec
+func (r *OVNNorthd) validate() error {
+	var allErrs field.ErrorList
+	basePath := field.NewPath("spec")
+
+	// the threads only build in parallel on CPUs of their own, a single thread is fine with less
+	cpu := r.Spec.Resources.Requests.Cpu()
+	if cpu.IsZero() {
+		cpu = r.Spec.Resources.Limits.Cpu()
+	}
+	if r.Spec.Tuning.NThreads > 1 && !cpu.IsZero() && cpu.MilliValue() < int64(r.Spec.Tuning.NThreads)*1000 {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("tuning").Child("nThreads"), r.Spec.Tuning.NThreads,
+			fmt.Sprintf("exceeds the %s CPU requested in resources", cpu.String())))
+	}
+
+	if len(allErrs) != 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "OVNNorthd"},
+			r.Name, allErrs)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TracePhasePending - waiting for the inputs of the trace
+	TracePhasePending = "Pending"
+	// TracePhaseRunning - the ovn-trace job is running
+	TracePhaseRunning = "Running"
+	// TracePhaseCompleted - the result of the trace is stored in the result ConfigMap
+	TracePhaseCompleted = "Completed"
+	// TracePhaseFailed - the ovn-trace job failed
+	TracePhaseFailed = "Failed"
+)
+
+// OVNTraceSpec defines the desired state of OVNTrace. Either the microflow, or the source port and the
+// destination IP have to be set. Changing the spec runs the trace again.
+type OVNTraceSpec struct {
+	// +kubebuilder:validation:Optional
+	// SBClusterRef - the SB OVNDBCluster to trace against. If not set, the SB OVNDBCluster of the namespace
+	// is used
+	SBClusterRef *OVNDBClusterRef `json:"sbClusterRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Datapath - the logical switch or router the microflow enters. It can be omitted if the microflow
+	// matches on inport.
+	Datapath string `json:"datapath,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Microflow - the ovn-trace microflow expression, e.g.
+	// inport == "lsp1" && eth.src == 00:00:00:00:00:01 && ip4.src == 10.0.0.1 && ip4.dst == 10.0.0.2
+	Microflow string `json:"microflow,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SourcePort - simplified form, the logical switch port sending the packet. Its MAC and first IP are
+	// taken from its port binding.
+	SourcePort string `json:"sourcePort,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DestinationIP - simplified form, the IPv4 or IPv6 address the packet is sent to. The destination MAC
+	// is the one of the logical switch port owning the address on the same switch, otherwise the one of the
+	// router port of the switch.
+	DestinationIP string `json:"destinationIP,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=detailed
+	// +kubebuilder:validation:Enum=detailed;summary;minimal
+	// Format - output format of ovn-trace
+	Format string `json:"format"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerImage - Container Image URL providing ovn-trace (will be set to the image of the SB
+	// OVNDBCluster if empty)
+	ContainerImage string `json:"containerImage,omitempty"`
+}
+
+// OVNTraceStatus defines the observed state of OVNTrace
+type OVNTraceStatus struct {
+	// ObservedGeneration - the most recent generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase - Pending, Running, Completed or Failed
+	Phase string `json:"phase,omitempty"`
+
+	// ResultConfigMap - name of the ConfigMap holding the output of ovn-trace under the trace key
+	ResultConfigMap string `json:"resultConfigMap,omitempty"`
+
+	// CompletionTime - time the trace finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase"
+//+kubebuilder:printcolumn:name="Result",type="string",JSONPath=".status.resultConfigMap",description="Result"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// OVNTrace is the Schema for the ovntraces API
+type OVNTrace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNTraceSpec   `json:"spec,omitempty"`
+	Status OVNTraceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OVNTraceList contains a list of OVNTrace
+type OVNTraceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNTrace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OVNTrace{}, &OVNTraceList{})
+}
+
+// IsReady - returns true if the trace completed
+func (instance OVNTrace) IsReady() bool {
+	return instance.Status.Conditions.IsTrue(condition.ReadyCondition)
+}
+
+// ValidateFlow - returns an error if neither the microflow nor the simplified form is set, or both are
+func (instance OVNTrace) ValidateFlow() error {
+	simplified := instance.Spec.SourcePort != "" || instance.Spec.DestinationIP != ""
+	if instance.Spec.Microflow != "" && simplified {
+		return fmt.Errorf("microflow and sourcePort/destinationIP are mutually exclusive")
+	}
+	if instance.Spec.Microflow == "" && (instance.Spec.SourcePort == "" || instance.Spec.DestinationIP == "") {
+		return fmt.Errorf("either microflow, or sourcePort and destinationIP are required")
+	}
+	return nil
+}
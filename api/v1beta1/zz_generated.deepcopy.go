@@ -26,6 +26,38 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNChassisLag) DeepCopyInto(out *OVNChassisLag) {
+	*out = *in
+	in.Pending.DeepCopyInto(&out.Pending)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNChassisLag.
+func (in *OVNChassisLag) DeepCopy() *OVNChassisLag {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNChassisLag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNConfigLag) DeepCopyInto(out *OVNConfigLag) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNConfigLag.
+func (in *OVNConfigLag) DeepCopy() *OVNConfigLag {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNConfigLag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNController) DeepCopyInto(out *OVNController) {
 	*out = *in
@@ -53,6 +85,29 @@ func (in *OVNController) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNControllerConfigPropagation) DeepCopyInto(out *OVNControllerConfigPropagation) {
+	*out = *in
+	if in.LaggingChassis != nil {
+		in, out := &in.LaggingChassis, &out.LaggingChassis
+		*out = make([]OVNChassisLag, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNControllerConfigPropagation.
+func (in *OVNControllerConfigPropagation) DeepCopy() *OVNControllerConfigPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNControllerConfigPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNControllerDebug) DeepCopyInto(out *OVNControllerDebug) {
 	*out = *in
@@ -125,6 +180,12 @@ func (in *OVNControllerSpec) DeepCopyInto(out *OVNControllerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.TLS = in.TLS
+	if in.SBClusterRef != nil {
+		in, out := &in.SBClusterRef, &out.SBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNControllerSpec.
@@ -169,6 +230,11 @@ func (in *OVNControllerStatus) DeepCopyInto(out *OVNControllerStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ConfigPropagation != nil {
+		in, out := &in.ConfigPropagation, &out.ConfigPropagation
+		*out = new(OVNControllerConfigPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNControllerStatus.
@@ -181,6 +247,21 @@ func (in *OVNControllerStatus) DeepCopy() *OVNControllerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNControllerTLS) DeepCopyInto(out *OVNControllerTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNControllerTLS.
+func (in *OVNControllerTLS) DeepCopy() *OVNControllerTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNControllerTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNDBCluster) DeepCopyInto(out *OVNDBCluster) {
 	*out = *in
@@ -208,6 +289,21 @@ func (in *OVNDBCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBClusterAutoHeal) DeepCopyInto(out *OVNDBClusterAutoHeal) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterAutoHeal.
+func (in *OVNDBClusterAutoHeal) DeepCopy() *OVNDBClusterAutoHeal {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBClusterAutoHeal)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNDBClusterDebug) DeepCopyInto(out *OVNDBClusterDebug) {
 	*out = *in
@@ -238,6 +334,36 @@ func (in *OVNDBClusterDefaults) DeepCopy() *OVNDBClusterDefaults {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBClusterExternal) DeepCopyInto(out *OVNDBClusterExternal) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterExternal.
+func (in *OVNDBClusterExternal) DeepCopy() *OVNDBClusterExternal {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBClusterExternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBClusterIntegrityCheck) DeepCopyInto(out *OVNDBClusterIntegrityCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterIntegrityCheck.
+func (in *OVNDBClusterIntegrityCheck) DeepCopy() *OVNDBClusterIntegrityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBClusterIntegrityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNDBClusterList) DeepCopyInto(out *OVNDBClusterList) {
 	*out = *in
@@ -270,6 +396,21 @@ func (in *OVNDBClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBClusterRef) DeepCopyInto(out *OVNDBClusterRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterRef.
+func (in *OVNDBClusterRef) DeepCopy() *OVNDBClusterRef {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNDBClusterSpec) DeepCopyInto(out *OVNDBClusterSpec) {
 	*out = *in
@@ -278,6 +419,7 @@ func (in *OVNDBClusterSpec) DeepCopyInto(out *OVNDBClusterSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	out.External = in.External
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -287,6 +429,14 @@ func (in *OVNDBClusterSpec) DeepCopyInto(out *OVNDBClusterSpec) {
 	}
 	out.Debug = in.Debug
 	in.Resources.DeepCopyInto(&out.Resources)
+	out.TLS = in.TLS
+	out.IntegrityCheck = in.IntegrityCheck
+	if in.ReplaceMembers != nil {
+		in, out := &in.ReplaceMembers, &out.ReplaceMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.AutoHeal = in.AutoHeal
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterSpec.
@@ -316,6 +466,11 @@ func (in *OVNDBClusterStatus) DeepCopyInto(out *OVNDBClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.NetworkAttachments != nil {
 		in, out := &in.NetworkAttachments, &out.NetworkAttachments
 		*out = make(map[string][]string, len(*in))
@@ -331,6 +486,27 @@ func (in *OVNDBClusterStatus) DeepCopyInto(out *OVNDBClusterStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.IntegrityCheck != nil {
+		in, out := &in.IntegrityCheck, &out.IntegrityCheck
+		*out = make(map[string]OVNDBMemberIntegrity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Replacements != nil {
+		in, out := &in.Replacements, &out.Replacements
+		*out = make(map[string]OVNDBMemberReplacement, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AutoHeals != nil {
+		in, out := &in.AutoHeals, &out.AutoHeals
+		*out = make([]OVNDBMemberHeal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterStatus.
@@ -343,6 +519,337 @@ func (in *OVNDBClusterStatus) DeepCopy() *OVNDBClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBClusterTLS) DeepCopyInto(out *OVNDBClusterTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBClusterTLS.
+func (in *OVNDBClusterTLS) DeepCopy() *OVNDBClusterTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBClusterTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMemberHeal) DeepCopyInto(out *OVNDBMemberHeal) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMemberHeal.
+func (in *OVNDBMemberHeal) DeepCopy() *OVNDBMemberHeal {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMemberHeal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMemberIntegrity) DeepCopyInto(out *OVNDBMemberIntegrity) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMemberIntegrity.
+func (in *OVNDBMemberIntegrity) DeepCopy() *OVNDBMemberIntegrity {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMemberIntegrity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMemberReplacement) DeepCopyInto(out *OVNDBMemberReplacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMemberReplacement.
+func (in *OVNDBMemberReplacement) DeepCopy() *OVNDBMemberReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMemberReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMigration) DeepCopyInto(out *OVNDBMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMigration.
+func (in *OVNDBMigration) DeepCopy() *OVNDBMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNDBMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMigrationList) DeepCopyInto(out *OVNDBMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNDBMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMigrationList.
+func (in *OVNDBMigrationList) DeepCopy() *OVNDBMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNDBMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMigrationSource) DeepCopyInto(out *OVNDBMigrationSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMigrationSource.
+func (in *OVNDBMigrationSource) DeepCopy() *OVNDBMigrationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMigrationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMigrationSpec) DeepCopyInto(out *OVNDBMigrationSpec) {
+	*out = *in
+	out.Source = in.Source
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMigrationSpec.
+func (in *OVNDBMigrationSpec) DeepCopy() *OVNDBMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDBMigrationStatus) DeepCopyInto(out *OVNDBMigrationStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RowCounts != nil {
+		in, out := &in.RowCounts, &out.RowCounts
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDBMigrationStatus.
+func (in *OVNDBMigrationStatus) DeepCopy() *OVNDBMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDBMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNGlobalConfig) DeepCopyInto(out *OVNGlobalConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNGlobalConfig.
+func (in *OVNGlobalConfig) DeepCopy() *OVNGlobalConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNGlobalConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNGlobalConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNGlobalConfigList) DeepCopyInto(out *OVNGlobalConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNGlobalConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNGlobalConfigList.
+func (in *OVNGlobalConfigList) DeepCopy() *OVNGlobalConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNGlobalConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNGlobalConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNGlobalConfigSpec) DeepCopyInto(out *OVNGlobalConfigSpec) {
+	*out = *in
+	if in.NBClusterRef != nil {
+		in, out := &in.NBClusterRef, &out.NBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
+	if in.SBClusterRef != nil {
+		in, out := &in.SBClusterRef, &out.SBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IPsec != nil {
+		in, out := &in.IPsec, &out.IPsec
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNGlobalConfigSpec.
+func (in *OVNGlobalConfigSpec) DeepCopy() *OVNGlobalConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNGlobalConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNGlobalConfigStatus) DeepCopyInto(out *OVNGlobalConfigStatus) {
+	*out = *in
+	if in.ManagedOptions != nil {
+		in, out := &in.ManagedOptions, &out.ManagedOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNGlobalConfigStatus.
+func (in *OVNGlobalConfigStatus) DeepCopy() *OVNGlobalConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNGlobalConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNNorthd) DeepCopyInto(out *OVNNorthd) {
 	*out = *in
@@ -370,6 +877,32 @@ func (in *OVNNorthd) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNNorthdConfigPropagation) DeepCopyInto(out *OVNNorthdConfigPropagation) {
+	*out = *in
+	if in.SBLag != nil {
+		in, out := &in.SBLag, &out.SBLag
+		*out = new(OVNConfigLag)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HVLag != nil {
+		in, out := &in.HVLag, &out.HVLag
+		*out = new(OVNConfigLag)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdConfigPropagation.
+func (in *OVNNorthdConfigPropagation) DeepCopy() *OVNNorthdConfigPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNNorthdConfigPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNNorthdDebug) DeepCopyInto(out *OVNNorthdDebug) {
 	*out = *in
@@ -400,6 +933,21 @@ func (in *OVNNorthdDefaults) DeepCopy() *OVNNorthdDefaults {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNNorthdInstance) DeepCopyInto(out *OVNNorthdInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdInstance.
+func (in *OVNNorthdInstance) DeepCopy() *OVNNorthdInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNNorthdInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNNorthdList) DeepCopyInto(out *OVNNorthdList) {
 	*out = *in
@@ -432,6 +980,21 @@ func (in *OVNNorthdList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNNorthdMetrics) DeepCopyInto(out *OVNNorthdMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdMetrics.
+func (in *OVNNorthdMetrics) DeepCopy() *OVNNorthdMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNNorthdMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVNNorthdSpec) DeepCopyInto(out *OVNNorthdSpec) {
 	*out = *in
@@ -449,6 +1012,19 @@ func (in *OVNNorthdSpec) DeepCopyInto(out *OVNNorthdSpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NBClusterRef != nil {
+		in, out := &in.NBClusterRef, &out.NBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
+	if in.SBClusterRef != nil {
+		in, out := &in.SBClusterRef, &out.SBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
+	out.TLS = in.TLS
+	in.Tuning.DeepCopyInto(&out.Tuning)
+	out.Metrics = in.Metrics
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdSpec.
@@ -486,6 +1062,16 @@ func (in *OVNNorthdStatus) DeepCopyInto(out *OVNNorthdStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]OVNNorthdInstance, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigPropagation != nil {
+		in, out := &in.ConfigPropagation, &out.ConfigPropagation
+		*out = new(OVNNorthdConfigPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdStatus.
@@ -498,6 +1084,153 @@ func (in *OVNNorthdStatus) DeepCopy() *OVNNorthdStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNNorthdTLS) DeepCopyInto(out *OVNNorthdTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdTLS.
+func (in *OVNNorthdTLS) DeepCopy() *OVNNorthdTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNNorthdTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNNorthdTuning) DeepCopyInto(out *OVNNorthdTuning) {
+	*out = *in
+	if in.ProbeInterval != nil {
+		in, out := &in.ProbeInterval, &out.ProbeInterval
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNorthdTuning.
+func (in *OVNNorthdTuning) DeepCopy() *OVNNorthdTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNNorthdTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNTrace) DeepCopyInto(out *OVNTrace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNTrace.
+func (in *OVNTrace) DeepCopy() *OVNTrace {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNTrace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNTrace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNTraceList) DeepCopyInto(out *OVNTraceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNTrace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNTraceList.
+func (in *OVNTraceList) DeepCopy() *OVNTraceList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNTraceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNTraceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNTraceSpec) DeepCopyInto(out *OVNTraceSpec) {
+	*out = *in
+	if in.SBClusterRef != nil {
+		in, out := &in.SBClusterRef, &out.SBClusterRef
+		*out = new(OVNDBClusterRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNTraceSpec.
+func (in *OVNTraceSpec) DeepCopy() *OVNTraceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNTraceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNTraceStatus) DeepCopyInto(out *OVNTraceStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNTraceStatus.
+func (in *OVNTraceStatus) DeepCopy() *OVNTraceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNTraceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVSExternalIDs) DeepCopyInto(out *OVSExternalIDs) {
 	*out = *in
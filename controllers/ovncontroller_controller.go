@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,12 +28,16 @@ import (
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
@@ -45,9 +50,13 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	nad "github.com/openstack-k8s-operators/lib-common/modules/common/networkattachment"
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovncontroller"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbcluster"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/podexec"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/propagation"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -79,8 +88,11 @@ func (r *OVNControllerReconciler) GetLogger(ctx context.Context) logr.Logger {
 // OVNControllerReconciler reconciles a OVNController object
 type OVNControllerReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// RestConfig is used to read the system-id of the chassis the client certificates are issued for
+	RestConfig *rest.Config
 }
 
 // GetClient -
@@ -93,6 +105,9 @@ func (r *OVNControllerReconciler) GetClient() client.Client {
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovncontrollers/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=create;delete;get;list;patch;update;watch
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch;update;delete;
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
@@ -189,6 +204,7 @@ func (r *OVNControllerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if instance.Status.NetworkAttachments == nil {
 		instance.Status.NetworkAttachments = map[string][]string{}
 	}
+	instance.Status.ObservedGeneration = instance.Generation
 
 	// Handle service delete
 	if !instance.DeletionTimestamp.IsZero() {
@@ -211,10 +227,28 @@ func (r *OVNControllerReconciler) SetupWithManager(mgr ctrl.Manager, ctx context
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
-		Watches(&source.Kind{Type: &v1beta1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(v1beta1.OVNDBClusterNamespaceMapFunc(crs, mgr.GetClient(), r.GetLogger(ctx)))).
+		Watches(&source.Kind{Type: &v1beta1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(v1beta1.OVNDBClusterConsumerMapFunc(crs, mgr.GetClient(), r.GetLogger(ctx)))).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.caSecretMapFunc)).
 		Complete(r)
 }
 
+// caSecretMapFunc - enqueues the OVNControllers using the Secret as their CA
+func (r *OVNControllerReconciler) caSecretMapFunc(obj client.Object) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	ovnControllerList := &v1beta1.OVNControllerList{}
+	if err := r.Client.List(context.Background(), ovnControllerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.GetLogger(context.Background()).Error(err, "Unable to retrieve OVNControllers")
+		return nil
+	}
+	for _, cr := range ovnControllerList.Items {
+		if cr.Spec.TLS.CASecretName == obj.GetName() {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+		}
+	}
+	return result
+}
+
 func (r *OVNControllerReconciler) reconcileDelete(ctx context.Context, instance *v1beta1.OVNController, helper *helper.Helper) (ctrl.Result, error) {
 	Log := r.GetLogger(ctx)
 
@@ -290,6 +324,29 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 	// ConfigMap
 	configMapVars := make(map[string]env.Setter)
 
+	// TLS input, the operator issues the per-chassis client certificates with the CA
+	caHash := ""
+	if instance.IsTLSEnabled() {
+		var ctrlResult ctrl.Result
+		caHash, ctrlResult, err = secret.VerifySecret(
+			ctx,
+			types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.TLS.CASecretName},
+			[]string{"tls.crt", "tls.key"},
+			helper.GetClient(),
+			time.Duration(10)*time.Second,
+		)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrlResult, err
+		}
+		configMapVars[instance.Spec.TLS.CASecretName] = env.SetValue(caHash)
+	}
+
 	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
 
 	//
@@ -463,7 +520,7 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 	}
 	// create DaemonSet - end
 
-	sbCluster, err := v1beta1.GetDBClusterByType(ctx, helper, instance.Namespace, map[string]string{}, v1beta1.SBDBType)
+	sbCluster, err := v1beta1.GetDBClusterForRef(ctx, helper, instance.Spec.SBClusterRef, instance.Namespace, v1beta1.SBDBType)
 	if err != nil {
 		Log.Info("No SB OVNDBCluster defined, deleting external ConfigMap")
 		cleanupConfigMapErr := r.deleteExternalConfigMaps(ctx, helper, instance)
@@ -474,7 +531,7 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 		return ctrl.Result{}, nil
 	}
 
-	ep, err := sbCluster.GetExternalEndpoint()
+	ep, err := sbCluster.GetExternalChassisEndpoint()
 	if err != nil || ep == "" {
 		Log.Info("No external endpoint defined for SB OVNDBCluster, deleting external ConfigMap")
 		cleanupConfigMapErr := r.deleteExternalConfigMaps(ctx, helper, instance)
@@ -494,9 +551,41 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 		}
 	}
 
+	// nb_cfg moves with the NB changes without any k8s event, it is polled
+	configCheck := r.reconcileConfigPropagation(ctx, instance, helper, sbCluster)
+
 	// create OVN Config Job - start
 	if instance.Status.NumberReady == instance.Status.DesiredNumberScheduled {
-		jobsDef, err := ovncontroller.ConfigJob(ctx, helper, r.Client, instance, sbCluster, serviceLabels)
+		// the config jobs only mount the certificate of their node, the CA key stays in the operator
+		certHashes := map[string]string{}
+		if instance.IsTLSEnabled() {
+			certHashes, ctrlResult, err = r.reconcileChassisCerts(ctx, instance, helper, sbCluster, serviceLabels)
+			if err != nil {
+				Log.Error(err, "Failed to issue the OVN chassis client certificates")
+				instance.Status.Conditions.Set(
+					condition.FalseCondition(
+						condition.ServiceConfigReadyCondition,
+						condition.ErrorReason,
+						condition.SeverityWarning,
+						condition.ServiceConfigReadyErrorMessage,
+						err.Error(),
+					),
+				)
+				return ctrl.Result{}, err
+			}
+			if (ctrlResult != ctrl.Result{}) {
+				instance.Status.Conditions.Set(
+					condition.FalseCondition(
+						condition.ServiceConfigReadyCondition,
+						condition.RequestedReason,
+						condition.SeverityInfo,
+						condition.ServiceConfigReadyMessage,
+					),
+				)
+				return ctrlResult, nil
+			}
+		}
+		jobsDef, err := ovncontroller.ConfigJob(ctx, helper, r.Client, instance, sbCluster, serviceLabels, certHashes)
 		if err != nil {
 			Log.Error(err, "Failed to create OVN controller configuration Job")
 			return ctrl.Result{}, err
@@ -525,6 +614,8 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 			}
 			if err != nil {
 				Log.Error(err, "Failed to configure OVN controller")
+				r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ConfigJobFailed",
+					"Configuration job %s of node %s failed: %s", jobDef.Name, jobDef.Spec.Template.Spec.NodeName, err.Error())
 				instance.Status.Conditions.Set(
 					condition.FalseCondition(
 						condition.ServiceConfigReadyCondition,
@@ -539,6 +630,8 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 			if configJob.HasChanged() {
 				instance.Status.Hash[configHashKey] = configJob.GetHash()
 				Log.Info(fmt.Sprintf("Job %s hash added - %s", jobDef.Name, instance.Status.Hash[configHashKey]))
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ConfigJobSucceeded",
+					"Configuration job %s of node %s succeeded", jobDef.Name, jobDef.Spec.Template.Spec.NodeName)
 			}
 		}
 		instance.Status.Conditions.MarkTrue(condition.ServiceConfigReadyCondition, condition.ServiceConfigReadyMessage)
@@ -550,7 +643,85 @@ func (r *OVNControllerReconciler) reconcileNormal(ctx context.Context, instance
 
 	Log.Info("Reconciled Service successfully")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: configCheck}, nil
+}
+
+// reconcileConfigPropagation - records the chassis behind the NB configuration from the SB_Global and
+// Chassis_Private sequence numbers and sets the ConfigLagging condition when a chassis stops catching up for
+// longer than the threshold. It is checked while the SB OVNDBCluster is ready, failures are only logged.
+// Returns when to check again.
+func (r *OVNControllerReconciler) reconcileConfigPropagation(
+	ctx context.Context,
+	instance *v1beta1.OVNController,
+	helper *helper.Helper,
+	sbCluster *v1beta1.OVNDBCluster,
+) time.Duration {
+	Log := r.GetLogger(ctx)
+	threshold := time.Duration(instance.Spec.ConfigLagThreshold) * time.Second
+
+	if !sbCluster.IsReady() {
+		return time.Duration(60) * time.Second
+	}
+	sbClient, err := ovndbcluster.Connect(ctx, helper, sbCluster, time.Duration(5)*time.Second)
+	if err != nil {
+		Log.Info(fmt.Sprintf("Unable to check the config propagation: %s", err.Error()))
+		return time.Duration(60) * time.Second
+	}
+	defer sbClient.Close()
+	nbCfg, err := propagation.GetSBGlobalNBCfg(sbClient)
+	var chassis []propagation.ChassisCfg
+	if err == nil {
+		chassis, err = propagation.GetChassisCfg(sbClient)
+	}
+	if err != nil {
+		Log.Info(fmt.Sprintf("Unable to check the config propagation: %s", err.Error()))
+		return time.Duration(60) * time.Second
+	}
+
+	previous := map[string]*v1beta1.OVNConfigLag{}
+	if instance.Status.ConfigPropagation != nil {
+		for i := range instance.Status.ConfigPropagation.LaggingChassis {
+			lagging := &instance.Status.ConfigPropagation.LaggingChassis[i]
+			previous[lagging.Name] = &lagging.Pending
+		}
+	}
+	now := metav1.Now()
+	status := &v1beta1.OVNControllerConfigPropagation{
+		NBCfg:         nbCfg,
+		Chassis:       int32(len(chassis)),
+		LastCheckTime: now,
+	}
+	lags := []*v1beta1.OVNConfigLag{}
+	stuck := []string{}
+	for _, cfg := range chassis {
+		lag := propagation.Track(previous[cfg.Name], nbCfg, cfg.NBCfg, now)
+		if lag == nil {
+			continue
+		}
+		status.LaggingChassis = append(status.LaggingChassis, v1beta1.OVNChassisLag{
+			Name:    cfg.Name,
+			NBCfg:   cfg.NBCfg,
+			Pending: *lag,
+		})
+		lags = append(lags, lag)
+		if propagation.Exceeds(lag, threshold, now) {
+			stuck = append(stuck, fmt.Sprintf("%s behind nb_cfg %d since %s",
+				cfg.Name, lag.NBCfg, lag.Since.UTC().Format(time.RFC3339)))
+		}
+	}
+	instance.Status.ConfigPropagation = status
+
+	if len(stuck) > 0 {
+		Log.Info(fmt.Sprintf("NB configuration not propagated: %s", strings.Join(stuck, ", ")))
+		instance.Status.Conditions.Set(condition.TrueCondition(
+			v1beta1.OVNConfigLaggingCondition,
+			v1beta1.OVNConfigLaggingMessage,
+			strings.Join(stuck, ", ")))
+	} else {
+		instance.Status.Conditions.Remove(v1beta1.OVNConfigLaggingCondition)
+	}
+
+	return propagation.NextCheck(lags, threshold, now)
 }
 
 // generateServiceConfigMaps - create configmaps which hold scripts and service configuration
@@ -596,7 +767,7 @@ func (r *OVNControllerReconciler) generateExternalConfigMaps(
 	// Create/update configmaps from templates
 	cmLabels := labels.GetLabels(instance, labels.GetGroupLabel(ovncontroller.ServiceName), map[string]string{})
 
-	externalEndpoint, err := sbCluster.GetExternalEndpoint()
+	externalEndpoint, err := sbCluster.GetExternalChassisEndpoint()
 	if err != nil {
 		return err
 	}
@@ -664,3 +835,98 @@ func (r *OVNControllerReconciler) createHashOfInputHashes(
 	}
 	return hash, changed, nil
 }
+
+// reconcileChassisCerts - issues the client certificate of the chassis on each node with the CA
+// and stores it in a per-node Secret, along with the CAs verifying the SB ovsdb-servers. The CN is
+// the system-id the chassis registers with in the SB DB, read from its ovsdb-server. Returns the
+// hash of the certificate Secret of each node.
+func (r *OVNControllerReconciler) reconcileChassisCerts(
+	ctx context.Context,
+	instance *v1beta1.OVNController,
+	helper *helper.Helper,
+	sbCluster *v1beta1.OVNDBCluster,
+	serviceLabels map[string]string,
+) (map[string]string, ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+	certHashes := map[string]string{}
+
+	caSecret, _, err := secret.GetSecret(ctx, helper, instance.Spec.TLS.CASecretName, instance.Namespace)
+	if err != nil {
+		return certHashes, ctrl.Result{}, err
+	}
+	externalCA, err := ovndbcluster.ExternalCA(ctx, helper, sbCluster)
+	if err != nil {
+		return certHashes, ctrl.Result{}, err
+	}
+	caBundle := ovncontroller.ChassisCABundle(caSecret.Data["tls.crt"], externalCA)
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(instance.Namespace), client.MatchingLabels(serviceLabels)); err != nil {
+		return certHashes, ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	for _, ovnPod := range podList.Items {
+		nodeName := ovnPod.Spec.NodeName
+		if nodeName == "" || ovnPod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		output, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &ovnPod, "ovsdb-server",
+			ovncontroller.SystemIDCommand())
+		systemID := ovncontroller.ParseSystemID(output)
+		if err != nil || systemID == "" {
+			Log.Info(fmt.Sprintf("system-id of the chassis on node %s not available yet: %v", nodeName, err))
+			return certHashes, ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+
+		certSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ovncontroller.ChassisCertSecretName(instance, nodeName),
+				Namespace: instance.Namespace,
+			},
+		}
+		op, err := controllerutil.CreateOrPatch(ctx, r.Client, certSecret, func() error {
+			certSecret.Labels = util.MergeStringMaps(certSecret.Labels, serviceLabels, map[string]string{
+				ovncontroller.ChassisCertLabel: instance.Name,
+				ovncontroller.ChassisNodeLabel: nodeName,
+			})
+			if !ovncontroller.ChassisCertValid(certSecret.Data, caSecret.Data["tls.crt"], systemID, now) {
+				data, err := ovncontroller.IssueChassisCert(caSecret.Data, systemID, now)
+				if err != nil {
+					return err
+				}
+				certSecret.Data = data
+			}
+			certSecret.Data["ca.crt"] = caBundle
+			return controllerutil.SetControllerReference(instance, certSecret, r.Scheme)
+		})
+		if err != nil {
+			return certHashes, ctrl.Result{}, err
+		}
+		if op != controllerutil.OperationResultNone {
+			Log.Info(fmt.Sprintf("Client certificate Secret %s of chassis %s %s", certSecret.Name, systemID, op))
+		}
+		certHashes[nodeName], err = secret.Hash(certSecret)
+		if err != nil {
+			return certHashes, ctrl.Result{}, err
+		}
+	}
+
+	// drop the certificates of the nodes the chassis is gone from
+	secretList := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secretList, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{ovncontroller.ChassisCertLabel: instance.Name}); err != nil {
+		return certHashes, ctrl.Result{}, err
+	}
+	for i := range secretList.Items {
+		if _, ok := certHashes[secretList.Items[i].Labels[ovncontroller.ChassisNodeLabel]]; ok {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &secretList.Items[i]); err != nil && !k8s_errors.IsNotFound(err) {
+			return certHashes, ctrl.Result{}, err
+		}
+		Log.Info(fmt.Sprintf("Deleted client certificate Secret %s", secretList.Items[i].Name))
+	}
+
+	return certHashes, ctrl.Result{}, nil
+}
@@ -18,16 +18,23 @@ package controllers
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
@@ -35,26 +42,37 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	nad "github.com/openstack-k8s-operators/lib-common/modules/common/networkattachment"
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbcluster"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/podexec"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+// maxAutoHeals - automatic rebuilds of members kept in the status
+const maxAutoHeals = 10
+
 // OVNDBClusterReconciler reconciles a OVNDBCluster object
 type OVNDBClusterReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient    kubernetes.Interface
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	RestConfig *rest.Config
 }
 
 // GetClient -
@@ -80,10 +98,17 @@ func (r *OVNDBClusterReconciler) GetLogger(ctx context.Context) logr.Logger {
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnnorthds,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovncontrollers,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;patch;update;delete;
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;patch;update;delete;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create;
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;delete;
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch;update;delete;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch
 
 // service account, role, rolebinding
@@ -128,6 +153,13 @@ func (r *OVNDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			condition.UnknownCondition(condition.RoleReadyCondition, condition.InitReason, condition.RoleReadyInitMessage),
 			condition.UnknownCondition(condition.RoleBindingReadyCondition, condition.InitReason, condition.RoleBindingReadyInitMessage),
 		)
+		if instance.IsExternal() {
+			// nothing gets deployed for an external database
+			cl = condition.CreateList(
+				condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+				condition.UnknownCondition(ovnv1.OVNDBReachableCondition, condition.InitReason, ovnv1.OVNDBReachableInitMessage),
+			)
+		}
 
 		instance.Status.Conditions.Init(&cl)
 
@@ -142,6 +174,11 @@ func (r *OVNDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if instance.Status.NetworkAttachments == nil {
 		instance.Status.NetworkAttachments = map[string][]string{}
 	}
+	if instance.Status.IntegrityCheck == nil {
+		instance.Status.IntegrityCheck = map[string]ovnv1.OVNDBMemberIntegrity{}
+	}
+	instance.Status.EphemeralStorage = instance.IsEphemeral()
+	instance.Status.ObservedGeneration = instance.Generation
 
 	helper, err := helper.NewHelper(
 		instance,
@@ -186,22 +223,64 @@ func (r *OVNDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OVNDBClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the dependents going away unblock the deletion of the OVNDBClusters they connect to
+	dependentsMapFunc := ovnv1.DBClusterConsumerMapFunc(mgr.GetClient(), r.GetLogger(context.Background()))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ovnv1.OVNDBCluster{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.tlsSecretMapFunc)).
+		Watches(&source.Kind{Type: &ovnv1.OVNNorthd{}}, handler.EnqueueRequestsFromMapFunc(dependentsMapFunc)).
+		Watches(&source.Kind{Type: &ovnv1.OVNController{}}, handler.EnqueueRequestsFromMapFunc(dependentsMapFunc)).
 		Complete(r)
 }
 
+// tlsSecretMapFunc - enqueues the OVNDBClusters using the Secret as their TLS certificate
+func (r *OVNDBClusterReconciler) tlsSecretMapFunc(obj client.Object) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	ovnDBList := &ovnv1.OVNDBClusterList{}
+	if err := r.Client.List(context.Background(), ovnDBList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.GetLogger(context.Background()).Error(err, "Unable to retrieve OVNDBClusters")
+		return nil
+	}
+	for _, cr := range ovnDBList.Items {
+		if cr.Spec.TLS.SecretName == obj.GetName() {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+		}
+	}
+	return result
+}
+
 func (r *OVNDBClusterReconciler) reconcileDelete(ctx context.Context, instance *ovnv1.OVNDBCluster, helper *helper.Helper) (ctrl.Result, error) {
 	Log := r.GetLogger(ctx)
 
 	Log.Info("Reconciling Service delete")
 
+	// Keep the database as long as northd or ovn-controller still use it
+	if !instance.IsForceDelete() {
+		dependents, err := ovnv1.GetDBClusterDependents(ctx, helper.GetClient(), instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(dependents) > 0 {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				ovnv1.OVNDBDeletionReadyCondition,
+				condition.RequestedReason,
+				condition.SeverityWarning,
+				ovnv1.OVNDBDeletionBlockedMessage,
+				strings.Join(dependents, ", ")))
+			Log.Info(fmt.Sprintf("Deletion blocked by dependent resources: %s", strings.Join(dependents, ", ")))
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+	}
+
 	// Service is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(instance, helper.GetFinalizer())
 	Log.Info("Reconciled Service delete successfully")
@@ -250,6 +329,10 @@ func (r *OVNDBClusterReconciler) reconcileNormal(ctx context.Context, instance *
 		return ctrl.Result{}, err
 	}
 
+	if instance.IsExternal() {
+		return r.reconcileExternal(ctx, instance, helper)
+	}
+
 	// Service account, role, binding
 	rbacRules := []rbacv1.PolicyRule{
 		{
@@ -314,6 +397,27 @@ func (r *OVNDBClusterReconciler) reconcileNormal(ctx context.Context, instance *
 	// ConfigMap
 	configMapVars := make(map[string]env.Setter)
 
+	// TLS input, the content hash rolls the pods on certificate rotation
+	if instance.IsTLSEnabled() {
+		tlsHash, ctrlResult, err := secret.VerifySecret(
+			ctx,
+			types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.TLS.SecretName},
+			[]string{"tls.crt", "tls.key", "ca.crt"},
+			helper.GetClient(),
+			time.Duration(10)*time.Second,
+		)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrlResult, err
+		}
+		configMapVars[instance.Spec.TLS.SecretName] = env.SetValue(tlsHash)
+	}
+
 	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
 
 	//
@@ -439,6 +543,22 @@ func (r *OVNDBClusterReconciler) reconcileNormal(ctx context.Context, instance *
 		return ctrlResult, err
 	}
 
+	if instance.IsActiveBackup() {
+		ctrlResult, err = r.reconcileActiveBackup(ctx, instance, helper, serviceLabels, serviceName)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	} else if *instance.Spec.Replicas > 1 {
+		ctrlResult, err = r.reconcileStaleMembers(ctx, instance, helper, serviceLabels, serviceName)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+
 	svcList, err := service.GetServicesListWithLabel(
 		ctx,
 		helper,
@@ -456,32 +576,58 @@ func (r *OVNDBClusterReconciler) reconcileNormal(ctx context.Context, instance *
 	}
 
 	if instance.Status.ReadyCount > 0 && len(svcList.Items) > 0 {
-		instance.Status.Conditions.MarkTrue(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage)
 		instance.Status.Conditions.MarkTrue(condition.ExposeServiceReadyCondition, condition.ExposeServiceReadyMessage)
 		dbAddress := []string{}
 		internalDbAddress := []string{}
+		chassisDbAddress := []string{}
+		internalChassisDbAddress := []string{}
 		raftAddress := []string{}
 		var svcPort int32
+		proto := "tcp"
+		if instance.IsTLSEnabled() {
+			proto = "ssl"
+		}
 		for _, svc := range svcList.Items {
 			svcPort = svc.Spec.Ports[0].Port
 
-			// Filter out headless services
-			if svc.Spec.ClusterIP != "None" {
-				// Test using hostname instead of ip for dbAddress connection
-				//serviceHostname := fmt.Sprintf("%s.%s.svc", svc.Name, svc.GetNamespace())
-				//dbAddress = append(dbAddress, fmt.Sprintf("tcp:%s:%d", serviceHostname, svc.Spec.Ports[0].Port))
+			// Filter out headless services, in active-backup mode only the active member is published
+			if svc.Spec.ClusterIP != "None" && (!instance.IsActiveBackup() || svc.Labels["statefulset.kubernetes.io/pod-name"] == instance.Status.ActiveMember) {
+				// the per-pod Services are named after the pods
+				host := svc.Spec.ClusterIP
+				switch instance.Spec.AddressMode {
+				case ovnv1.PodDNSAddressMode:
+					host = ovndbcluster.PodFQDN(instance, serviceName, svc.Name)
+				case ovnv1.ServiceDNSAddressMode:
+					host = ovndbcluster.ServiceFQDN(instance, svc.Name)
+				}
 
-				internalDbAddress = append(internalDbAddress, fmt.Sprintf("tcp:%s:%d", svc.Spec.ClusterIP, svcPort))
-				raftAddress = append(raftAddress, fmt.Sprintf("tcp:%s:%d", svc.Spec.ClusterIP, svc.Spec.Ports[1].Port))
+				internalDbAddress = append(internalDbAddress, fmt.Sprintf("%s:%s:%d", proto, host, svcPort))
+				if !instance.IsActiveBackup() {
+					raftAddress = append(raftAddress, fmt.Sprintf("%s:%s:%d", proto, host, svc.Spec.Ports[1].Port))
+				}
+				if instance.Spec.EnableRBAC {
+					internalChassisDbAddress = append(internalChassisDbAddress, fmt.Sprintf("%s:%s:%d", proto, host, v1beta1.DBPortSBChassis))
+				}
 			}
 		}
 
 		// External dbAddress if networkAttachment is used
 		if instance.Spec.NetworkAttachment != "" {
 			net := instance.Namespace + "/" + instance.Spec.NetworkAttachment
-			if netStat, ok := instance.Status.NetworkAttachments[net]; ok {
+			netStat, ok := instance.Status.NetworkAttachments[net]
+			if instance.IsActiveBackup() {
+				netStat, err = r.getActiveMemberIPs(ctx, instance, helper, net)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				ok = len(netStat) > 0
+			}
+			if ok {
 				for _, instanceIP := range netStat {
-					dbAddress = append(dbAddress, fmt.Sprintf("tcp:%s:%d", instanceIP, svcPort))
+					dbAddress = append(dbAddress, fmt.Sprintf("%s:%s:%d", proto, instanceIP, svcPort))
+					if instance.Spec.EnableRBAC {
+						chassisDbAddress = append(chassisDbAddress, fmt.Sprintf("%s:%s:%d", proto, instanceIP, v1beta1.DBPortSBChassis))
+					}
 				}
 			}
 		}
@@ -489,13 +635,947 @@ func (r *OVNDBClusterReconciler) reconcileNormal(ctx context.Context, instance *
 		// Set DB Addresses
 		instance.Status.InternalDBAddress = strings.Join(internalDbAddress, ",")
 		instance.Status.DBAddress = strings.Join(dbAddress, ",")
+		// Set the RBAC restricted DB Addresses used by the chassis
+		instance.Status.InternalChassisDBAddress = strings.Join(internalChassisDbAddress, ",")
+		instance.Status.ChassisDBAddress = strings.Join(chassisDbAddress, ",")
 		// Set RaftAddress
 		instance.Status.RaftAddress = strings.Join(raftAddress, ",")
+
+		// Set the address of the leader Service
+		instance.Status.InternalLeaderDBAddress = ""
+		if instance.Spec.LeaderService {
+			leaderSvc, err := service.GetServiceWithName(
+				ctx, helper, ovndbcluster.LeaderServiceName(serviceName), instance.Namespace)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			host := leaderSvc.Spec.ClusterIP
+			if instance.Spec.AddressMode != ovnv1.ClusterIPAddressMode {
+				host = ovndbcluster.ServiceFQDN(instance, leaderSvc.Name)
+			}
+			instance.Status.InternalLeaderDBAddress = fmt.Sprintf("%s:%s:%d", proto, host, svcPort)
+		}
+	}
+
+	// The RAFT state of the members is polled, there is no k8s event for a leader change
+	statuses := map[string]ovndbcluster.ClusterStatus{}
+	raftCluster := !instance.IsActiveBackup() && *instance.Spec.Replicas > 1
+	if !instance.IsActiveBackup() {
+		if instance.Status.ReadyCount > 0 {
+			statuses, err = r.getClusterStatuses(ctx, instance, helper, serviceLabels, serviceName)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if raftCluster {
+			r.reconcileClusterEvents(instance, statuses)
+		}
+		err = r.reconcileRoleLabels(ctx, instance, helper, statuses, serviceLabels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Only a cluster with all the members up and joined is Ready, one serving with fewer members is Degraded
+	clusterFormed := false
+	if instance.Status.ReadyCount == *instance.Spec.Replicas {
+		clusterFormed = r.isClusterFormed(ctx, instance, statuses)
+	}
+	if clusterFormed {
+		instance.Status.Conditions.MarkTrue(condition.DeploymentReadyCondition, condition.DeploymentReadyMessage)
+		instance.Status.Conditions.Remove(ovnv1.OVNDBClusterDegradedCondition)
+	} else {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.DeploymentReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.DeploymentReadyRunningMessage))
+		// all the members ready but not joined yet are still forming the cluster
+		if instance.Status.ReadyCount > 0 && instance.Status.ReadyCount < *instance.Spec.Replicas {
+			instance.Status.Conditions.Set(condition.TrueCondition(
+				ovnv1.OVNDBClusterDegradedCondition,
+				ovnv1.OVNDBClusterDegradedMessage,
+				instance.Status.ReadyCount,
+				*instance.Spec.Replicas))
+		} else {
+			instance.Status.Conditions.Remove(ovnv1.OVNDBClusterDegradedCondition)
+		}
+	}
+
+	if !instance.IsActiveBackup() {
+		ctrlResult, err = r.reconcileReplaceMembers(ctx, instance, helper, statuses, serviceName)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+
+		ctrlResult, err = r.reconcileAutoHeal(ctx, instance, helper, statuses, serviceLabels, serviceName)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			// a heal held back by the minimum interval must not delay the polling of the RAFT state either
+			return soonerResult(ctrlResult, ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}), nil
+		}
+
+		sts := sfset.GetStatefulSet()
+		ctrlResult, err = r.reconcileRollout(ctx, instance, helper, &sts, statuses, serviceLabels, serviceName)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+
+	// the next integrity check may be a day away, it must not delay the polling of the RAFT state
+	integrityResult, err := r.reconcileIntegrityCheck(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return integrityResult, err
+	}
+
+	if !clusterFormed {
+		return soonerResult(integrityResult, ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}), nil
 	}
+
 	Log.Info("Reconciled Service successfully")
+	if raftCluster {
+		return soonerResult(integrityResult, ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}), nil
+	}
+	return integrityResult, nil
+}
+
+// soonerResult - returns the result requeueing first, an empty result does not requeue at all
+func soonerResult(a ctrl.Result, b ctrl.Result) ctrl.Result {
+	if a.Requeue && a.RequeueAfter == 0 || b == (ctrl.Result{}) {
+		return a
+	}
+	if b.Requeue && b.RequeueAfter == 0 || a == (ctrl.Result{}) {
+		return b
+	}
+	if b.RequeueAfter < a.RequeueAfter {
+		return b
+	}
+	return a
+}
+
+// reconcileIntegrityCheck - periodically runs the integrity check job of each member and records the result
+func (r *OVNDBClusterReconciler) reconcileIntegrityCheck(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	if !instance.Spec.IntegrityCheck.Enabled {
+		instance.Status.Conditions.Remove(ovnv1.OVNDBIntegrityCondition)
+		instance.Status.IntegrityCheck = map[string]ovnv1.OVNDBMemberIntegrity{}
+		return ctrl.Result{}, nil
+	}
+
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	interval := time.Duration(instance.Spec.IntegrityCheck.Interval) * time.Minute
+	requeueAfter := interval
+	members := map[string]ovnv1.OVNDBMemberIntegrity{}
+	for _, ovnPod := range podList.Items {
+		member, checked := instance.Status.IntegrityCheck[ovnPod.Name]
+		members[ovnPod.Name] = member
+		if ovnPod.Spec.NodeName == "" {
+			continue
+		}
+		nextCheck := member.LastCheckTime.Add(interval)
+		if checked && time.Now().Before(nextCheck) {
+			if time.Until(nextCheck) < requeueAfter {
+				requeueAfter = time.Until(nextCheck)
+			}
+			continue
+		}
+
+		// the last check time identifies the run, a finished check schedules the next one
+		runID := member.LastCheckTime.UTC().Format(time.RFC3339)
+		hashKey := "integrity-check-" + ovnPod.Name
+		checkJob := job.NewJob(
+			ovndbcluster.IntegrityCheckJob(instance, ovnPod, serviceLabels, runID),
+			hashKey,
+			false,
+			time.Duration(10)*time.Second,
+			instance.Status.Hash[hashKey],
+		)
+		ctrlResult, err := checkJob.DoJob(ctx, helper)
+		if err != nil && !k8s_errors.IsInternalError(err) {
+			return ctrl.Result{}, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		if !checkJob.HasChanged() {
+			continue
+		}
+		instance.Status.Hash[hashKey] = checkJob.GetHash()
+
+		// DoJob reports a failed job as an internal error
+		degraded := err != nil
+		if degraded {
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "IntegrityCheckFailed",
+				"Integrity check of the DB files of member %s failed", ovnPod.Name)
+		} else if member.Degraded {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "IntegrityCheckPassed",
+				"Integrity check of the DB files of member %s passed", ovnPod.Name)
+		}
+		Log.Info(fmt.Sprintf("Integrity check of member %s finished, degraded: %t", ovnPod.Name, degraded))
+		members[ovnPod.Name] = ovnv1.OVNDBMemberIntegrity{
+			LastCheckTime: metav1.Now(),
+			Degraded:      degraded,
+		}
+	}
+	instance.Status.IntegrityCheck = members
+
+	degradedMembers := []string{}
+	for name, member := range members {
+		if member.Degraded {
+			degradedMembers = append(degradedMembers, name)
+		}
+	}
+	if len(degradedMembers) > 0 {
+		sort.Strings(degradedMembers)
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNDBIntegrityCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			ovnv1.OVNDBIntegrityErrorMessage,
+			strings.Join(degradedMembers, ", ")))
+	} else {
+		instance.Status.Conditions.MarkTrue(ovnv1.OVNDBIntegrityCondition, ovnv1.OVNDBIntegrityReadyMessage)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileExternal - publishes the endpoint of the database managed outside of the operator, the readiness
+// is given by a periodic connectivity check from the operator
+func (r *OVNDBClusterReconciler) reconcileExternal(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	var tlsConfig *tls.Config
+	if instance.IsTLSEnabled() {
+		_, ctrlResult, err := secret.VerifySecret(
+			ctx,
+			types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.TLS.SecretName},
+			[]string{"ca.crt"},
+			helper.GetClient(),
+			time.Duration(10)*time.Second,
+		)
+		if err == nil {
+			var tlsSecret *corev1.Secret
+			tlsSecret, _, err = secret.GetSecret(ctx, helper, instance.Spec.TLS.SecretName, instance.Namespace)
+			if err == nil {
+				tlsConfig, err = ovndbcluster.ExternalTLSConfig(tlsSecret.Data)
+			}
+		}
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrlResult, err
+		}
+	}
+	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
+
+	// the clients fail over between the remotes themselves
+	instance.Status.InternalDBAddress = instance.Spec.External.Endpoint
+	instance.Status.DBAddress = instance.Spec.External.Endpoint
+
+	err := ovndbcluster.CheckRemotes(ctx, instance.GetExternalRemotes(), tlsConfig, time.Duration(5)*time.Second)
+	if err != nil {
+		Log.Info(fmt.Sprintf("External OVN DB not reachable: %s", err.Error()))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNDBReachableCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			ovnv1.OVNDBReachableErrorMessage,
+			err.Error()))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+	instance.Status.Conditions.MarkTrue(ovnv1.OVNDBReachableCondition, ovnv1.OVNDBReachableMessage)
+
+	Log.Info("Reconciled external OVN DB successfully")
+	return ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}, nil
+}
+
+// reconcileActiveBackup - makes sure exactly one ready member is the active ovsdb-server in active-backup
+// mode. The current active member is kept as long as it is ready, otherwise a ready backup gets promoted.
+// All members start as backups, so any other member reporting active state gets demoted again.
+func (r *OVNDBClusterReconciler) reconcileActiveBackup(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	serviceName string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	readyPods := []corev1.Pod{}
+	for _, ovnPod := range podList.Items {
+		if ovndbcluster.IsPodReady(ovnPod) {
+			readyPods = append(readyPods, ovnPod)
+		}
+	}
+	if len(readyPods) == 0 {
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+	sort.Slice(readyPods, func(i, j int) bool { return readyPods[i].Name < readyPods[j].Name })
+
+	active := readyPods[0]
+	for _, ovnPod := range readyPods {
+		if ovnPod.Name == instance.Status.ActiveMember {
+			active = ovnPod
+		}
+	}
+
+	ctlSocket := ovndbcluster.CtlSocket(instance)
+	for _, ovnPod := range readyPods {
+		syncStatus, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &ovnPod, serviceName,
+			[]string{"ovs-appctl", "-t", ctlSocket, "ovsdb-server/sync-status"})
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to get the sync status of member %s", ovnPod.Name))
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+		isActive := strings.Contains(syncStatus, "state: active")
+
+		var command string
+		if ovnPod.Name == active.Name && !isActive {
+			command = "ovsdb-server/disconnect-active-ovsdb-server"
+		} else if ovnPod.Name != active.Name && isActive {
+			command = "ovsdb-server/connect-active-ovsdb-server"
+		} else {
+			continue
+		}
+		_, err = podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &ovnPod, serviceName,
+			[]string{"ovs-appctl", "-t", ctlSocket, command})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		Log.Info(fmt.Sprintf("Switched role of member %s with %s", ovnPod.Name, command))
+	}
+
+	if instance.Status.ActiveMember != active.Name {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ActiveMemberChanged",
+			"Member %s is the active ovsdb-server, previous active member: %q", active.Name, instance.Status.ActiveMember)
+		instance.Status.ActiveMember = active.Name
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getClusterStatuses - returns the cluster/status by pod name of the ready members
+func (r *OVNDBClusterReconciler) getClusterStatuses(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	serviceName string,
+) (map[string]ovndbcluster.ClusterStatus, error) {
+	Log := r.GetLogger(ctx)
+
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return nil, err
+	}
+	statuses := map[string]ovndbcluster.ClusterStatus{}
+	for _, ovnPod := range podList.Items {
+		if !ovndbcluster.IsPodReady(ovnPod) {
+			continue
+		}
+		output, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &ovnPod, serviceName,
+			ovndbcluster.ClusterStatusCommand(instance))
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to get the cluster status of member %s", ovnPod.Name))
+			continue
+		}
+		statuses[ovnPod.Name] = ovndbcluster.ParseClusterStatus(output)
+	}
+	return statuses, nil
+}
+
+// isClusterFormed - returns true if all the members joined the cluster. In active-backup mode a member has
+// to be active.
+func (r *OVNDBClusterReconciler) isClusterFormed(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	statuses map[string]ovndbcluster.ClusterStatus,
+) bool {
+	Log := r.GetLogger(ctx)
+
+	if instance.IsActiveBackup() {
+		return instance.Status.ActiveMember != ""
+	}
+	// a single member is a cluster on its own
+	if *instance.Spec.Replicas <= 1 {
+		return true
+	}
+
+	if len(statuses) != int(*instance.Spec.Replicas) {
+		return false
+	}
+	for name, status := range statuses {
+		if status.Status != "cluster member" || len(status.Servers) != int(*instance.Spec.Replicas) {
+			Log.Info(fmt.Sprintf("Member %s did not join the cluster yet: %s, %d servers",
+				name, status.Status, len(status.Servers)))
+			return false
+		}
+	}
+	return true
+}
+
+// reconcileRoleLabels - keeps the RoleLabel of the members in line with their RAFT role. The label is removed
+// from members which are not ready, it is kept on a ready member whose role is unknown.
+func (r *OVNDBClusterReconciler) reconcileRoleLabels(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	statuses map[string]ovndbcluster.ClusterStatus,
+	serviceLabels map[string]string,
+) error {
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		ovnPod := &podList.Items[i]
+		role := ""
+		if status, ok := statuses[ovnPod.Name]; ok {
+			role = ovndbcluster.RoleFollower
+			if status.Role == "leader" {
+				role = ovndbcluster.RoleLeader
+			}
+		} else if ovndbcluster.IsPodReady(*ovnPod) {
+			continue
+		}
+		if ovnPod.Labels[ovndbcluster.RoleLabel] == role {
+			continue
+		}
+
+		patch := client.MergeFrom(ovnPod.DeepCopy())
+		if role == "" {
+			delete(ovnPod.Labels, ovndbcluster.RoleLabel)
+		} else {
+			if ovnPod.Labels == nil {
+				ovnPod.Labels = map[string]string{}
+			}
+			ovnPod.Labels[ovndbcluster.RoleLabel] = role
+		}
+		err = helper.GetClient().Patch(ctx, ovnPod, patch)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileClusterEvents - records the RAFT leader, membership and election timer of the cluster and emits
+// an Event for each change
+func (r *OVNDBClusterReconciler) reconcileClusterEvents(
+	instance *ovnv1.OVNDBCluster,
+	statuses map[string]ovndbcluster.ClusterStatus,
+) {
+	leader := ""
+	for name, status := range statuses {
+		if status.Role == "leader" {
+			leader = name
+		}
+	}
+	// without leader, e.g. during an election, there is nothing to compare with
+	if leader == "" {
+		return
+	}
+
+	if leader != instance.Status.Leader {
+		if instance.Status.Leader != "" {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "LeaderChanged",
+				"RAFT leadership moved from %s to %s", instance.Status.Leader, leader)
+		} else {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "LeaderChanged", "%s is the RAFT leader", leader)
+		}
+		instance.Status.Leader = leader
+	}
+
+	members := []string{}
+	for _, address := range statuses[leader].Servers {
+		members = append(members, address)
+	}
+	sort.Strings(members)
+	if len(instance.Status.Members) > 0 {
+		for _, address := range members {
+			if !contains(instance.Status.Members, address) {
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "MemberJoined",
+					"Server at %s joined the RAFT cluster", address)
+			}
+		}
+		for _, address := range instance.Status.Members {
+			if !contains(members, address) {
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "MemberLeft",
+					"Server at %s left the RAFT cluster", address)
+			}
+		}
+	}
+	instance.Status.Members = members
+
+	timer := statuses[leader].ElectionTimer
+	if instance.Status.ElectionTimer != 0 && timer != instance.Status.ElectionTimer {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ElectionTimerChanged",
+			"RAFT election timer changed from %d to %d ms", instance.Status.ElectionTimer, timer)
+	}
+	instance.Status.ElectionTimer = timer
+}
+
+// reconcileRollout - in clustered mode the StatefulSet uses the OnDelete strategy and the operator rolls out
+// a new revision one member at a time. Followers are restarted first, the leadership is moved away from the
+// leader before restarting it, so the clients don't wait for an election timeout. The next member is only
+// restarted once all the members joined the cluster again and a leader got elected.
+func (r *OVNDBClusterReconciler) reconcileRollout(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	sts *appsv1.StatefulSet,
+	statuses map[string]ovndbcluster.ClusterStatus,
+	serviceLabels map[string]string,
+	serviceName string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	if sts.Status.UpdateRevision == "" {
+		return ctrl.Result{}, nil
+	}
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	outdated := []corev1.Pod{}
+	for _, ovnPod := range podList.Items {
+		if ovnPod.Labels[appsv1.ControllerRevisionHashLabelKey] != sts.Status.UpdateRevision {
+			outdated = append(outdated, ovnPod)
+		}
+	}
+	if len(outdated) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	leader := ""
+	for name, status := range statuses {
+		if status.Role == "leader" {
+			leader = name
+		}
+	}
+	if instance.Status.ReadyCount != *instance.Spec.Replicas || !r.isClusterFormed(ctx, instance, statuses) ||
+		(*instance.Spec.Replicas > 1 && leader == "") {
+		Log.Info(fmt.Sprintf("Waiting for the cluster to settle before restarting the next of %d members", len(outdated)))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+
+	// followers first, from the highest ordinal like the RollingUpdate strategy
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].Name > outdated[j].Name })
+	next := outdated[0]
+	for _, ovnPod := range outdated {
+		if ovnPod.Name != leader {
+			next = ovnPod
+			break
+		}
+	}
+
+	if next.Name == leader && *instance.Spec.Replicas > 1 {
+		// a forced election, the member is restarted once it is a follower
+		_, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &next, serviceName,
+			ovndbcluster.ClusterTransferLeadershipCommand(instance))
+		if err == nil {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "LeadershipTransferred",
+				"Moved the RAFT leadership away from %s before restarting it", next.Name)
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+		Log.Error(err, fmt.Sprintf("Unable to transfer the leadership of member %s", next.Name))
+
+		// the other members join the cluster from scratch on restart, so they can leave it before.
+		// The first member keeps its DB file and has to stay in the cluster.
+		if next.Name != serviceName+"-0" {
+			_, err = podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &next, serviceName,
+				ovndbcluster.ClusterLeaveCommand(instance))
+			if err != nil {
+				Log.Error(err, fmt.Sprintf("Unable to leave the cluster with member %s", next.Name))
+			}
+		}
+	}
+
+	err = helper.GetClient().Delete(ctx, &next)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "MemberRestarted",
+		"Restarted member %s to roll out revision %s", next.Name, sts.Status.UpdateRevision)
+	Log.Info(fmt.Sprintf("Restarted member %s to roll out revision %s", next.Name, sts.Status.UpdateRevision))
+
+	return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+}
+
+// reconcileReplaceMembers - replaces the members listed in replaceMembers one at a time and waits for the
+// replacement to join the cluster before moving on to the next member
+func (r *OVNDBClusterReconciler) reconcileReplaceMembers(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	statuses map[string]ovndbcluster.ClusterStatus,
+	serviceName string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	// the automatic replacements are tracked until the member rejoined
+	members := append([]string{}, instance.Spec.ReplaceMembers...)
+	for name, replacement := range instance.Status.Replacements {
+		if contains(members, name) {
+			continue
+		}
+		if replacement.Automatic && replacement.Phase != ovnv1.ReplacementPhaseCompleted {
+			members = append(members, name)
+		} else {
+			delete(instance.Status.Replacements, name)
+		}
+	}
+
+	for _, name := range members {
+		replacement, ok := instance.Status.Replacements[name]
+		if !ok {
+			return r.replaceMember(ctx, instance, helper, statuses, serviceName, name)
+		}
+		if replacement.Phase == ovnv1.ReplacementPhaseCompleted {
+			continue
+		}
+
+		ovnPod := &corev1.Pod{}
+		err := helper.GetClient().Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: name}, ovnPod)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		status, joined := statuses[name]
+		joined = joined && err == nil && string(ovnPod.UID) != replacement.PodUID &&
+			status.Status == "cluster member" && status.ServerID != replacement.ServerID
+		if !joined || !r.isClusterFormed(ctx, instance, statuses) {
+			Log.Info(fmt.Sprintf("Waiting for the replacement of member %s to join the cluster", name))
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+
+		replacement.Phase = ovnv1.ReplacementPhaseCompleted
+		instance.Status.Replacements[name] = replacement
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "MemberReplaced",
+			"Member %s rejoined the cluster as server %s, replacing server %q", name, status.ServerID, replacement.ServerID)
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileAutoHeal - rebuilds a member crashlooping because of corrupt DB files. The DB files of a member
+// which restarted restartThreshold times are checked by a job, a member failing the check is replaced like
+// the ones in replaceMembers. Only one member is rebuilt at a time, and at most one every minInterval.
+func (r *OVNDBClusterReconciler) reconcileAutoHeal(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	statuses map[string]ovndbcluster.ClusterStatus,
+	serviceLabels map[string]string,
+	serviceName string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	if !instance.Spec.AutoHeal.Enabled {
+		return ctrl.Result{}, nil
+	}
+	for _, replacement := range instance.Status.Replacements {
+		if replacement.Phase != ovnv1.ReplacementPhaseCompleted {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	crashLooping := []corev1.Pod{}
+	for _, ovnPod := range podList.Items {
+		if ovnPod.Spec.NodeName != "" &&
+			ovndbcluster.IsCrashLooping(ovnPod, serviceName, instance.Spec.AutoHeal.RestartThreshold) {
+			crashLooping = append(crashLooping, ovnPod)
+		}
+	}
+	if len(crashLooping) == 0 {
+		return ctrl.Result{}, nil
+	}
+	sort.Slice(crashLooping, func(i, j int) bool { return crashLooping[i].Name < crashLooping[j].Name })
+	ovnPod := crashLooping[0]
+
+	leader := ""
+	for name, status := range statuses {
+		if status.Role == "leader" {
+			leader = name
+		}
+	}
+	if leader == "" {
+		Log.Info(fmt.Sprintf("Not healing member %s, the other members have no leader", ovnPod.Name))
+		return ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}, nil
+	}
+	if n := len(instance.Status.AutoHeals); n > 0 {
+		nextHeal := instance.Status.AutoHeals[n-1].Time.Add(
+			time.Duration(instance.Spec.AutoHeal.MinInterval) * time.Minute)
+		if time.Now().Before(nextHeal) {
+			Log.Info(fmt.Sprintf("Not healing member %s before %s", ovnPod.Name, nextHeal))
+			return ctrl.Result{RequeueAfter: time.Until(nextHeal)}, nil
+		}
+	}
+
+	// each pod of the member is checked once
+	hashKey := "heal-check-" + ovnPod.Name
+	checkJob := job.NewJob(
+		ovndbcluster.HealCheckJob(instance, ovnPod, serviceLabels, string(ovnPod.UID)),
+		hashKey,
+		false,
+		time.Duration(10)*time.Second,
+		instance.Status.Hash[hashKey],
+	)
+	ctrlResult, err := checkJob.DoJob(ctx, helper)
+	if err != nil && !k8s_errors.IsInternalError(err) {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+	if !checkJob.HasChanged() {
+		return ctrl.Result{}, nil
+	}
+
+	// DoJob reports a failed job as an internal error
+	if err == nil {
+		instance.Status.Hash[hashKey] = checkJob.GetHash()
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CrashLoopNotHealed",
+			"Member %s is crashlooping but its DB files passed the integrity check, not rebuilding it", ovnPod.Name)
+		return ctrl.Result{}, nil
+	}
+
+	ctrlResult, err = r.replaceMember(ctx, instance, helper, statuses, serviceName, ovnPod.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	replacement, ok := instance.Status.Replacements[ovnPod.Name]
+	if !ok {
+		return ctrlResult, nil
+	}
+	instance.Status.Hash[hashKey] = checkJob.GetHash()
+	replacement.Automatic = true
+	instance.Status.Replacements[ovnPod.Name] = replacement
+
+	restarts := ovndbcluster.RestartCount(ovnPod, serviceName)
+	instance.Status.AutoHeals = append(instance.Status.AutoHeals, ovnv1.OVNDBMemberHeal{
+		Member:   ovnPod.Name,
+		ServerID: replacement.ServerID,
+		Restarts: restarts,
+		Time:     metav1.Now(),
+	})
+	if len(instance.Status.AutoHeals) > maxAutoHeals {
+		instance.Status.AutoHeals = instance.Status.AutoHeals[len(instance.Status.AutoHeals)-maxAutoHeals:]
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeWarning, "MemberAutoHealed",
+		"Rebuilding member %s from the cluster after %d restarts, its DB files failed the integrity check",
+		ovnPod.Name, restarts)
+	Log.Info(fmt.Sprintf("Rebuilding member %s with corrupt DB files", ovnPod.Name))
+
+	return ctrlResult, nil
+}
+
+// replaceMember - kicks the server of the member out of the cluster through the leader, then deletes the PVC
+// and pod of the member. The StatefulSet recreates the member with an empty DB, which joins the cluster as a
+// new server.
+func (r *OVNDBClusterReconciler) replaceMember(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	statuses map[string]ovndbcluster.ClusterStatus,
+	serviceName string,
+	name string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	leader := ""
+	for podName, status := range statuses {
+		if status.Role == "leader" && podName != name {
+			leader = podName
+		}
+	}
+	if leader == "" {
+		Log.Info(fmt.Sprintf("Waiting for a leader to replace member %s", name))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+
+	// a member which is down is only known by its RAFT address, which holds the name of its pod
+	serverID := ""
+	if status, ok := statuses[name]; ok {
+		serverID = status.ServerID
+	} else {
+		for id, address := range statuses[leader].Servers {
+			if strings.Contains(address, name+".") {
+				serverID = id
+			}
+		}
+	}
+	if serverID != "" {
+		leaderPod := &corev1.Pod{}
+		err := helper.GetClient().Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: leader}, leaderPod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		_, err = podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, leaderPod, serviceName,
+			ovndbcluster.ClusterKickCommand(instance, serverID))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		Log.Info(fmt.Sprintf("Kicked server %s of member %s out of the cluster", serverID, name))
+	}
+
+	if !instance.IsEphemeral() {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ovndbcluster.PVCName(instance, name),
+				Namespace: instance.Namespace,
+			},
+		}
+		err := helper.GetClient().Delete(ctx, pvc)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	podUID := ""
+	ovnPod := &corev1.Pod{}
+	err := helper.GetClient().Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: name}, ovnPod)
+	if err == nil {
+		podUID = string(ovnPod.UID)
+		err = helper.GetClient().Delete(ctx, ovnPod)
+	}
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Replacements == nil {
+		instance.Status.Replacements = map[string]ovnv1.OVNDBMemberReplacement{}
+	}
+	instance.Status.Replacements[name] = ovnv1.OVNDBMemberReplacement{
+		Phase:    ovnv1.ReplacementPhaseRejoining,
+		ServerID: serverID,
+		PodUID:   podUID,
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "MemberReplacing",
+		"Kicked server %q of member %s out of the cluster and deleted the member", serverID, name)
+
+	return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+}
+
+// reconcileStaleMembers - a restarted member rejoins the cluster from scratch with a new server ID, in ephemeral
+// storage mode and for all but the first member with persistent storage, whose DB files are removed on start.
+// The server ID it used before the restart only leaves the cluster on a graceful stop, otherwise it is still
+// part of the cluster and gets kicked out.
+func (r *OVNDBClusterReconciler) reconcileStaleMembers(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+	serviceName string,
+) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	podList, err := ovndbcluster.OVNDBPods(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	statuses := []ovndbcluster.ClusterStatus{}
+	var kicker *corev1.Pod
+	for i, ovnPod := range podList.Items {
+		if !ovndbcluster.IsPodReady(ovnPod) {
+			continue
+		}
+		output, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, &ovnPod, serviceName,
+			ovndbcluster.ClusterStatusCommand(instance))
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to get the cluster status of member %s", ovnPod.Name))
+			return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+		}
+		statuses = append(statuses, ovndbcluster.ParseClusterStatus(output))
+		kicker = &podList.Items[i]
+	}
+
+	// the server ID currently used at each member address
+	currentIDs := map[string]string{}
+	for _, status := range statuses {
+		if status.Status == "cluster member" {
+			currentIDs[status.Address] = status.ServerID
+		}
+	}
+	stale := map[string]string{}
+	for _, status := range statuses {
+		for serverID, address := range status.Servers {
+			if currentID, ok := currentIDs[address]; ok && currentID != serverID {
+				stale[serverID] = address
+			}
+		}
+	}
+
+	for serverID, address := range stale {
+		_, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, kicker, serviceName,
+			ovndbcluster.ClusterKickCommand(instance, serverID))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "StaleMemberKicked",
+			"Kicked server %s of the restarted member at %s out of the cluster", serverID, address)
+		Log.Info(fmt.Sprintf("Kicked stale server %s at %s", serverID, address))
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// getActiveMemberIPs - returns the IPs of the active member on the given network
+func (r *OVNDBClusterReconciler) getActiveMemberIPs(
+	ctx context.Context,
+	instance *ovnv1.OVNDBCluster,
+	helper *helper.Helper,
+	net string,
+) ([]string, error) {
+	if instance.Status.ActiveMember == "" {
+		return []string{}, nil
+	}
+	activePod, err := helper.GetKClient().CoreV1().Pods(instance.Namespace).Get(ctx, instance.Status.ActiveMember, metav1.GetOptions{})
+	if err != nil {
+		return []string{}, err
+	}
+	netsStatus, err := nad.GetNetworkStatusFromAnnotation(activePod.Annotations)
+	if err != nil {
+		return []string{}, err
+	}
+	for _, netStat := range netsStatus {
+		if netStat.Name == net {
+			return netStat.IPs, nil
+		}
+	}
+	return []string{}, nil
+}
+
 func (r *OVNDBClusterReconciler) reconcileServices(
 	ctx context.Context,
 	instance *ovnv1.OVNDBCluster,
@@ -557,6 +1637,32 @@ func (r *OVNDBClusterReconciler) reconcileServices(
 		// create service - end
 	}
 
+	// The leader Service is labeled apart from the per-pod Services, which are counted below
+	leaderServiceLabels := map[string]string{
+		common.AppSelector: ovndbcluster.LeaderServiceName(serviceName),
+	}
+	if instance.Spec.LeaderService {
+		svc, err := service.NewService(
+			ovndbcluster.LeaderService(ovndbcluster.LeaderServiceName(serviceName), instance, leaderServiceLabels, serviceLabels),
+			time.Duration(5)*time.Second,
+			nil,
+		)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		ctrlResult, err := svc.CreateOrPatch(ctx, helper)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrl.Result{}, nil
+		}
+	} else {
+		err = service.DeleteServicesWithLabel(ctx, helper, instance, leaderServiceLabels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Delete any extra services left after scale down
 	svcList, err := service.GetServicesListWithLabel(
 		ctx,
@@ -601,16 +1707,25 @@ func (r *OVNDBClusterReconciler) generateServiceConfigMaps(
 	templateParameters["OVN_LOG_LEVEL"] = instance.Spec.LogLevel
 	templateParameters["SERVICE_NAME"] = serviceName
 	templateParameters["NAMESPACE"] = instance.GetNamespace()
+	templateParameters["CLUSTER_DOMAIN"] = instance.GetClusterDomain()
 	templateParameters["DB_TYPE"] = strings.ToLower(instance.Spec.DBType)
-	templateParameters["DB_PORT"] = 6641
-	templateParameters["RAFT_PORT"] = 6643
+	templateParameters["DB_PORT"] = v1beta1.DBPortNB
+	templateParameters["RAFT_PORT"] = v1beta1.RaftPortNB
 	if instance.Spec.DBType == v1beta1.SBDBType {
-		templateParameters["DB_PORT"] = 6642
-		templateParameters["RAFT_PORT"] = 6644
+		templateParameters["DB_PORT"] = v1beta1.DBPortSB
+		templateParameters["RAFT_PORT"] = v1beta1.RaftPortSB
 	}
 	templateParameters["OVN_ELECTION_TIMER"] = instance.Spec.ElectionTimer
 	templateParameters["OVN_INACTIVITY_PROBE"] = instance.Spec.InactivityProbe
 	templateParameters["OVN_PROBE_INTERVAL_TO_ACTIVE"] = instance.Spec.ProbeIntervalToActive
+	templateParameters["TLS"] = instance.IsTLSEnabled()
+	templateParameters["OVN_DB_CERT_PATH"] = ovndbcluster.TLSCertPath
+	templateParameters["OVN_DB_KEY_PATH"] = ovndbcluster.TLSKeyPath
+	templateParameters["OVN_DB_CA_CERT_PATH"] = ovndbcluster.TLSCACertPath
+	templateParameters["OVN_RBAC"] = instance.Spec.EnableRBAC
+	templateParameters["CHASSIS_DB_PORT"] = v1beta1.DBPortSBChassis
+	templateParameters["DB_MODE"] = instance.Spec.Mode
+	templateParameters["EPHEMERAL"] = instance.IsEphemeral()
 	cms := []util.Template{
 		// ScriptsConfigMap
 		{
@@ -0,0 +1,391 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbcluster"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbmigration"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OVNDBMigrationReconciler reconciles a OVNDBMigration object
+type OVNDBMigrationReconciler struct {
+	client.Client
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// GetClient -
+func (r *OVNDBMigrationReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetScheme -
+func (r *OVNDBMigrationReconciler) GetScheme() *runtime.Scheme {
+	return r.Scheme
+}
+
+// GetLogger returns a logger object with a prefix of "controller.name" and additional controller context fields
+func (r *OVNDBMigrationReconciler) GetLogger(ctx context.Context) logr.Logger {
+	return log.FromContext(ctx).WithName("Controllers").WithName("OVNDBMigration")
+}
+
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbmigrations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbmigrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbmigrations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch;update;delete;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile - OVN DB migration
+func (r *OVNDBMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
+	Log := r.GetLogger(ctx)
+
+	// Fetch the OVNDBMigration instance
+	instance := &ovnv1.OVNDBMigration{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		// initialize conditions used later as Status=Unknown
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+			condition.UnknownCondition(condition.ServiceConfigReadyCondition, condition.InitReason, condition.ServiceConfigReadyInitMessage),
+			condition.UnknownCondition(ovnv1.OVNDBMigrationCompletedCondition, condition.InitReason, ovnv1.OVNDBMigrationCompletedInitMessage),
+		)
+
+		instance.Status.Conditions.Init(&cl)
+		instance.Status.Phase = ovnv1.MigrationPhasePending
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the Ready condition based on the sub conditions
+		if instance.Status.Conditions.AllSubConditionIsTrue() {
+			instance.Status.Conditions.MarkTrue(
+				condition.ReadyCondition, condition.ReadyMessage)
+		} else {
+			// something is not ready so reset the Ready condition
+			instance.Status.Conditions.MarkUnknown(
+				condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage)
+			// and recalculate it based on the state of the rest of the conditions
+			instance.Status.Conditions.Set(
+				instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		err := helper.PatchInstance(ctx, instance)
+		if err != nil {
+			_err = err
+			return
+		}
+	}()
+
+	// Nothing owned needs cleanup beyond the garbage collection, and a finished migration is final
+	if !instance.DeletionTimestamp.IsZero() ||
+		instance.Status.Phase == ovnv1.MigrationPhaseCompleted ||
+		instance.Status.Phase == ovnv1.MigrationPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OVNDBMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ovnv1.OVNDBMigration{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &ovnv1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(r.targetClusterMapFunc)).
+		Complete(r)
+}
+
+// targetClusterMapFunc - enqueues the OVNDBMigrations copying data into the OVNDBCluster
+func (r *OVNDBMigrationReconciler) targetClusterMapFunc(obj client.Object) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	migrations := &ovnv1.OVNDBMigrationList{}
+	if err := r.Client.List(context.Background(), migrations, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.GetLogger(context.Background()).Error(err, "Unable to retrieve OVNDBMigrations")
+		return nil
+	}
+	for _, migration := range migrations.Items {
+		if migration.Spec.TargetCluster == obj.GetName() {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&migration)})
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	return nil
+}
+
+func (r *OVNDBMigrationReconciler) reconcileNormal(ctx context.Context, instance *ovnv1.OVNDBMigration, helper *helper.Helper) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	Log.Info("Reconciling OVN DB migration")
+
+	// the target has to be up to take the data
+	target := &ovnv1.OVNDBCluster{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.TargetCluster}, target)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if err != nil || !target.IsReady() {
+		Log.Info(fmt.Sprintf("Target OVNDBCluster %s not ready yet", instance.Spec.TargetCluster))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.InputReadyWaitingMessage))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+
+	if instance.Spec.Source.TLSSecretName != "" {
+		_, ctrlResult, err := secret.VerifySecret(
+			ctx,
+			types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.Source.TLSSecretName},
+			[]string{"tls.crt", "tls.key", "ca.crt"},
+			helper.GetClient(),
+			time.Duration(10)*time.Second,
+		)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrlResult, err
+		}
+	}
+	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
+
+	err = r.generateServiceConfigMaps(ctx, helper, instance, target)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.Conditions.MarkTrue(condition.ServiceConfigReadyCondition, condition.ServiceConfigReadyMessage)
+
+	// in continuous sync the next copy is due one interval after the last one, the cutover doesn't wait
+	if instance.Status.Phase == ovnv1.MigrationPhaseSyncing && !instance.Spec.Cutover {
+		nextSync := instance.Status.LastSyncTime.Add(time.Duration(instance.Spec.SyncInterval) * time.Second)
+		if time.Now().Before(nextSync) {
+			return ctrl.Result{RequeueAfter: time.Until(nextSync)}, nil
+		}
+	}
+	if instance.Status.Phase == ovnv1.MigrationPhasePending {
+		instance.Status.Phase = ovnv1.MigrationPhaseMigrating
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNDBMigrationCompletedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			ovnv1.OVNDBMigrationRunningMessage))
+	}
+
+	serviceLabels := map[string]string{
+		common.AppSelector: "ovndbmigration",
+		"ovndbmigration":   instance.Name,
+	}
+	jobName := ovndbmigration.JobName(instance)
+	syncJob := job.NewJob(
+		ovndbmigration.SyncJob(instance, target, serviceLabels),
+		"sync",
+		false,
+		time.Duration(10)*time.Second,
+		instance.Status.Hash["sync"],
+	)
+	ctrlResult, err := syncJob.DoJob(ctx, helper)
+	if err != nil && k8s_errors.IsInternalError(err) {
+		// DoJob reports a failed job as an internal error
+		Log.Info(fmt.Sprintf("OVN DB migration job %s failed", jobName))
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "SyncFailed", "Copy of the data by job %s failed", jobName)
+		instance.Status.Phase = ovnv1.MigrationPhaseFailed
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNDBMigrationCompletedCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			ovnv1.OVNDBMigrationErrorMessage,
+			fmt.Sprintf("job %s failed", jobName)))
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+	if !syncJob.HasChanged() {
+		return ctrl.Result{}, nil
+	}
+	instance.Status.Hash["sync"] = syncJob.GetHash()
+
+	syncResult, err := r.getSyncResult(ctx, instance, jobName)
+	if err != nil {
+		// the copy itself succeeded, only its report is missing
+		Log.Info(fmt.Sprintf("No result of OVN DB migration job %s: %s", jobName, err.Error()))
+		syncResult = &ovndbmigration.SyncResult{Final: instance.IsFinalSync()}
+	} else {
+		instance.Status.Checksum = syncResult.Checksum
+		instance.Status.RowCounts = syncResult.RowCounts
+	}
+	now := metav1.Now()
+	instance.Status.LastSyncTime = &now
+	instance.Status.Syncs++
+	Log.Info(fmt.Sprintf("OVN DB migration job %s finished, checksum %s", jobName, instance.Status.Checksum))
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SyncSucceeded",
+		"Copy of the data into %s finished, checksum %s", target.Name, instance.Status.Checksum)
+
+	if syncResult.Final {
+		instance.Status.Phase = ovnv1.MigrationPhaseCompleted
+		instance.Status.Conditions.MarkTrue(ovnv1.OVNDBMigrationCompletedCondition, ovnv1.OVNDBMigrationCompletedMessage)
+		Log.Info("Reconciled OVN DB migration successfully")
+		return ctrl.Result{}, nil
+	}
+
+	instance.Status.Phase = ovnv1.MigrationPhaseSyncing
+	instance.Status.Conditions.Set(condition.FalseCondition(
+		ovnv1.OVNDBMigrationCompletedCondition,
+		condition.RequestedReason,
+		condition.SeverityInfo,
+		ovnv1.OVNDBMigrationSyncingMessage,
+		instance.Status.Syncs))
+	return ctrl.Result{RequeueAfter: time.Duration(instance.Spec.SyncInterval) * time.Second}, nil
+}
+
+// getSyncResult - reads the result of the copy from the termination message of the succeeded job pod
+func (r *OVNDBMigrationReconciler) getSyncResult(
+	ctx context.Context,
+	instance *ovnv1.OVNDBMigration,
+	jobName string,
+) (*ovndbmigration.SyncResult, error) {
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels{"job-name": jobName})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				return ovndbmigration.ParseSyncResult(status.State.Terminated.Message)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no succeeded pod of job %s found", jobName)
+}
+
+func (r *OVNDBMigrationReconciler) generateServiceConfigMaps(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNDBMigration,
+	target *ovnv1.OVNDBCluster,
+) error {
+	cmLabels := labels.GetLabels(instance, labels.GetGroupLabel("ovndbmigration"), map[string]string{})
+
+	templateParameters := make(map[string]interface{})
+	templateParameters["DB_NAME"] = ovndbcluster.DBName(target)
+	templateParameters["DB_TYPE"] = strings.ToLower(target.Spec.DBType)
+	templateParameters["SOURCE"] = instance.Spec.Source.Endpoint
+	templateParameters["TARGET"] = target.Status.InternalDBAddress
+	templateParameters["SOURCE_TLS"] = instance.Spec.Source.TLSSecretName != ""
+	templateParameters["SOURCE_TLS_PATH"] = ovndbmigration.SourceTLSPath
+	templateParameters["TARGET_TLS"] = target.IsTLSEnabled()
+	templateParameters["TARGET_TLS_PATH"] = ovndbmigration.TargetTLSPath
+	cms := []util.Template{
+		// ScriptsConfigMap
+		{
+			Name:          fmt.Sprintf("%s-scripts", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeScripts,
+			InstanceType:  instance.Kind,
+			Labels:        cmLabels,
+			ConfigOptions: templateParameters,
+		},
+	}
+	return configmap.EnsureConfigMaps(ctx, h, instance, cms, &map[string]env.Setter{})
+}
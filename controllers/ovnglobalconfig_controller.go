@@ -0,0 +1,306 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbcluster"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovnglobalconfig"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovsdb"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OVNGlobalConfigReconciler reconciles a OVNGlobalConfig object
+type OVNGlobalConfigReconciler struct {
+	client.Client
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// GetClient -
+func (r *OVNGlobalConfigReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetScheme -
+func (r *OVNGlobalConfigReconciler) GetScheme() *runtime.Scheme {
+	return r.Scheme
+}
+
+// GetLogger returns a logger object with a prefix of "controller.name" and additional controller context fields
+func (r *OVNGlobalConfigReconciler) GetLogger(ctx context.Context) logr.Logger {
+	return log.FromContext(ctx).WithName("Controllers").WithName("OVNGlobalConfig")
+}
+
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnglobalconfigs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnglobalconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnglobalconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile - OVN global config
+func (r *OVNGlobalConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
+	Log := r.GetLogger(ctx)
+
+	// Fetch the OVNGlobalConfig instance
+	instance := &ovnv1.OVNGlobalConfig{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// The values stay in the database.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		// initialize conditions used later as Status=Unknown
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+			condition.UnknownCondition(ovnv1.OVNGlobalConfigAppliedCondition, condition.InitReason, ovnv1.OVNGlobalConfigAppliedInitMessage),
+		)
+
+		instance.Status.Conditions.Init(&cl)
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the Ready condition based on the sub conditions
+		if instance.Status.Conditions.AllSubConditionIsTrue() {
+			instance.Status.Conditions.MarkTrue(
+				condition.ReadyCondition, condition.ReadyMessage)
+		} else {
+			// something is not ready so reset the Ready condition
+			instance.Status.Conditions.MarkUnknown(
+				condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage)
+			// and recalculate it based on the state of the rest of the conditions
+			instance.Status.Conditions.Set(
+				instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		err := helper.PatchInstance(ctx, instance)
+		if err != nil {
+			_err = err
+			return
+		}
+	}()
+
+	// Nothing is owned, the values set stay in the database
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OVNGlobalConfigReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Context) error {
+	Log := r.GetLogger(ctx)
+	crs := &ovnv1.OVNGlobalConfigList{}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ovnv1.OVNGlobalConfig{}).
+		Watches(&source.Kind{Type: &ovnv1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(ovnv1.OVNDBClusterConsumerMapFunc(crs, mgr.GetClient(), Log))).
+		Complete(r)
+}
+
+func (r *OVNGlobalConfigReconciler) reconcileNormal(ctx context.Context, instance *ovnv1.OVNGlobalConfig, helper *helper.Helper) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	Log.Info("Reconciling OVN global config")
+
+	// the values are applied through the internal endpoints, once they are known
+	nbCluster, err := ovnv1.GetDBClusterForRef(ctx, helper, instance.Spec.NBClusterRef, instance.Namespace, ovnv1.NBDBType)
+	var nbRemotes, sbRemotes []string
+	if err == nil {
+		nbRemotes, err = nbCluster.GetInternalRemotes()
+	}
+	var sbCluster *ovnv1.OVNDBCluster
+	if err == nil {
+		sbCluster, err = ovnv1.GetDBClusterForRef(ctx, helper, instance.Spec.SBClusterRef, instance.Namespace, ovnv1.SBDBType)
+	}
+	if err == nil {
+		sbRemotes, err = sbCluster.GetInternalRemotes()
+	}
+	if err != nil {
+		Log.Info(fmt.Sprintf("NB or SB OVNDBCluster not available: %s", err.Error()))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.InputReadyWaitingMessage))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+
+	nbTLSConfig, err := ovndbcluster.ClientTLSConfig(ctx, helper, nbCluster)
+	var sbTLSConfig *tls.Config
+	if err == nil {
+		sbTLSConfig, err = ovndbcluster.ClientTLSConfig(ctx, helper, sbCluster)
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.InputReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
+
+	resync := ctrl.Result{RequeueAfter: time.Duration(instance.Spec.ResyncInterval) * time.Second}
+	err = r.applyGlobals(ctx, instance, nbRemotes, nbTLSConfig, sbRemotes, sbTLSConfig)
+	if err != nil {
+		Log.Info(fmt.Sprintf("OVN global config not applied: %s", err.Error()))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNGlobalConfigAppliedCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			ovnv1.OVNGlobalConfigAppliedErrorMessage,
+			err.Error()))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+	instance.Status.Conditions.MarkTrue(ovnv1.OVNGlobalConfigAppliedCondition, ovnv1.OVNGlobalConfigAppliedMessage)
+
+	Log.Info("Reconciled OVN global config successfully")
+	return resync, nil
+}
+
+// applyGlobals - brings the NB_Global and SB_Global rows to the values of the spec. ovn-northd copies the
+// NB_Global values into SB_Global as well, setting them in both keeps the chassis configured while it is
+// down and restores SB_Global values changed by hand. Changes found while the spec is the one applied last
+// are reported as drift.
+func (r *OVNGlobalConfigReconciler) applyGlobals(
+	ctx context.Context,
+	instance *ovnv1.OVNGlobalConfig,
+	nbRemotes []string,
+	nbTLSConfig *tls.Config,
+	sbRemotes []string,
+	sbTLSConfig *tls.Config,
+) error {
+	Log := r.GetLogger(ctx)
+
+	specHash, err := util.ObjectHash(instance.Spec)
+	if err != nil {
+		return err
+	}
+
+	drift, err := r.applyGlobal(ctx, instance, nbRemotes, nbTLSConfig, ovnglobalconfig.GetNBGlobal)
+	if err != nil {
+		return err
+	}
+	sbDrift, err := r.applyGlobal(ctx, instance, sbRemotes, sbTLSConfig, ovnglobalconfig.GetSBGlobal)
+	if err != nil {
+		return err
+	}
+	drift = append(drift, sbDrift...)
+
+	if len(drift) > 0 && instance.Status.Hash["spec"] == specHash {
+		Log.Info(fmt.Sprintf("Restored values changed outside of the OVNGlobalConfig: %s",
+			strings.Join(drift, ", ")))
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "DriftRestored",
+			"Restored values changed outside of the OVNGlobalConfig: %s", strings.Join(drift, ", "))
+		instance.Status.Drift = drift
+		now := metav1.Now()
+		instance.Status.LastDriftTime = &now
+	}
+
+	instance.Status.ManagedOptions = ovnglobalconfig.ManagedOptions(instance)
+	instance.Status.Hash["spec"] = specHash
+	return nil
+}
+
+// applyGlobal - brings the NB_Global or SB_Global row to the values of the spec and returns the values
+// changed, prefixed with the table
+func (r *OVNGlobalConfigReconciler) applyGlobal(
+	ctx context.Context,
+	instance *ovnv1.OVNGlobalConfig,
+	remotes []string,
+	tlsConfig *tls.Config,
+	getGlobal func(*ovsdb.Client) (*ovnglobalconfig.Global, error),
+) ([]string, error) {
+	dbClient, err := ovsdb.Dial(ctx, remotes, tlsConfig, time.Duration(5)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer dbClient.Close()
+
+	global, err := getGlobal(dbClient)
+	if err != nil {
+		return nil, err
+	}
+	changes := ovnglobalconfig.GetChanges(instance, global)
+	if changes.IsEmpty() {
+		return nil, nil
+	}
+	err = ovnglobalconfig.Apply(dbClient, global, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for _, column := range changes.Describe() {
+		changed = append(changed, global.Table()+" "+column)
+	}
+	return changed, nil
+}
@@ -19,38 +19,59 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	"github.com/openstack-k8s-operators/lib-common/modules/common"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/deployment"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	common_labels "github.com/openstack-k8s-operators/lib-common/modules/common/labels"
 	nad "github.com/openstack-k8s-operators/lib-common/modules/common/networkattachment"
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovndbcluster"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovnglobalconfig"
 	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovnnorthd"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/podexec"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/propagation"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // OVNNorthdReconciler reconciles a OVNNorthd object
 type OVNNorthdReconciler struct {
 	client.Client
-	Kclient kubernetes.Interface
-	Scheme  *runtime.Scheme
+	Kclient    kubernetes.Interface
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	RestConfig *rest.Config
 }
 
 // GetClient -
@@ -73,9 +94,15 @@ func (r *OVNNorthdReconciler) GetLogger(ctx context.Context) logr.Logger {
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnnorthds/finalizers,verbs=update
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters/status,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovnglobalconfigs,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;patch;update;delete;
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list;watch
 
 // service account, role, rolebinding
@@ -112,6 +139,8 @@ func (r *OVNNorthdReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		// initialize conditions used later as Status=Unknown
 		cl := condition.CreateList(
 			condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+			condition.UnknownCondition(ovnv1.OVNNorthdNBDBReadyCondition, condition.InitReason, ovnv1.OVNDBClusterInitMessage),
+			condition.UnknownCondition(ovnv1.OVNNorthdSBDBReadyCondition, condition.InitReason, ovnv1.OVNDBClusterInitMessage),
 			condition.UnknownCondition(condition.NetworkAttachmentsReadyCondition, condition.InitReason, condition.NetworkAttachmentsReadyInitMessage),
 			condition.UnknownCondition(condition.DeploymentReadyCondition, condition.InitReason, condition.DeploymentReadyInitMessage),
 			condition.UnknownCondition(condition.ServiceAccountReadyCondition, condition.InitReason, condition.ServiceAccountReadyInitMessage),
@@ -129,6 +158,7 @@ func (r *OVNNorthdReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if instance.Status.NetworkAttachments == nil {
 		instance.Status.NetworkAttachments = map[string][]string{}
 	}
+	instance.Status.ObservedGeneration = instance.Generation
 
 	helper, err := helper.NewHelper(
 		instance,
@@ -179,13 +209,32 @@ func (r *OVNNorthdReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Con
 		For(&ovnv1.OVNNorthd{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
-		Watches(&source.Kind{Type: &ovnv1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(ovnv1.OVNDBClusterNamespaceMapFunc(crs, mgr.GetClient(), Log))).
+		Watches(&source.Kind{Type: &ovnv1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(ovnv1.OVNDBClusterConsumerMapFunc(crs, mgr.GetClient(), Log))).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.tlsSecretMapFunc)).
 		Complete(r)
 }
 
+// tlsSecretMapFunc - enqueues the OVNNorthds using the Secret as their TLS client certificate
+func (r *OVNNorthdReconciler) tlsSecretMapFunc(obj client.Object) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	northdList := &ovnv1.OVNNorthdList{}
+	if err := r.Client.List(context.Background(), northdList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.GetLogger(context.Background()).Error(err, "Unable to retrieve OVNNorthds")
+		return nil
+	}
+	for _, cr := range northdList.Items {
+		if cr.Spec.TLS.SecretName == obj.GetName() {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+		}
+	}
+	return result
+}
+
 func (r *OVNNorthdReconciler) reconcileDelete(ctx context.Context, instance *ovnv1.OVNNorthd, helper *helper.Helper) (ctrl.Result, error) {
 	Log := r.GetLogger(ctx)
 
@@ -254,6 +303,55 @@ func (r *OVNNorthdReconciler) reconcileNormal(ctx context.Context, instance *ovn
 		return rbacResult, nil
 	}
 
+	// TLS input, the content hash rolls the pods on certificate rotation
+	inputVars := map[string]env.Setter{}
+	if instance.IsTLSEnabled() {
+		tlsHash, ctrlResult, err := secret.VerifySecret(
+			ctx,
+			types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.TLS.SecretName},
+			[]string{"tls.crt", "tls.key", "ca.crt"},
+			helper.GetClient(),
+			time.Duration(10)*time.Second,
+		)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrlResult, err
+		}
+		inputVars[instance.Spec.TLS.SecretName] = env.SetValue(tlsHash)
+
+		err = r.generateCABundleConfigMap(ctx, helper, instance, &inputVars)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Spec.Metrics.Enabled {
+		err = r.generateScriptsConfigMap(ctx, helper, instance, &inputVars)
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.InputReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				condition.InputReadyErrorMessage,
+				err.Error()))
+			return ctrl.Result{}, err
+		}
+	}
+	inputHash, err := util.ObjectHash(env.MergeEnvs([]corev1.EnvVar{}, inputVars))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
 
 	//
@@ -315,16 +413,42 @@ func (r *OVNNorthdReconciler) reconcileNormal(ctx context.Context, instance *ovn
 
 	nbEndpoint, err := getInternalEndpoint(ctx, helper, instance, v1beta1.NBDBType)
 	if err != nil {
-		return ctrlResult, err
+		return ctrl.Result{}, err
 	}
 	sbEndpoint, err := getInternalEndpoint(ctx, helper, instance, v1beta1.SBDBType)
 	if err != nil {
-		return ctrlResult, err
+		return ctrl.Result{}, err
+	}
+	if nbEndpoint == "" || sbEndpoint == "" {
+		// the OVNDBCluster watch triggers the reconcile as well, the requeue covers clusters of other namespaces
+		Log.Info("Waiting for the OVNDBClusters", "NB", nbEndpoint, "SB", sbEndpoint)
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+	if !instance.IsTLSEnabled() && (strings.HasPrefix(nbEndpoint, "ssl:") || strings.HasPrefix(sbEndpoint, "ssl:")) {
+		err = fmt.Errorf("the DB clusters serve SSL, tls.secretName is required")
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.InputReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	err = r.reconcileMetrics(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the pause state is read by the starting pods, it is not part of the input hash to pause without a restart
+	err = r.generatePauseConfigMap(ctx, helper, instance)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// Define a new Deployment object
 	depl := deployment.NewDeployment(
-		ovnnorthd.Deployment(instance, serviceLabels, serviceAnnotations, nbEndpoint, sbEndpoint),
+		ovnnorthd.Deployment(instance, serviceLabels, serviceAnnotations, nbEndpoint, sbEndpoint, inputHash),
 		time.Duration(5)*time.Second,
 	)
 
@@ -374,23 +498,587 @@ func (r *OVNNorthdReconciler) reconcileNormal(ctx context.Context, instance *ovn
 	}
 	// create Deployment - end
 
+	result := ctrl.Result{}
+	err = r.reconcileTuningOptions(ctx, instance, helper)
+	if err != nil {
+		Log.Info(fmt.Sprintf("Unable to store the tuning options in NB_Global: %s", err.Error()))
+		result = ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}
+	}
+
+	pending, err := r.reconcilePause(ctx, instance, helper, serviceLabels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	instance.Status.Paused = instance.Spec.Paused && len(pending) == 0
+	if len(pending) > 0 {
+		message := ovnv1.OVNNorthdResumingMessage
+		if instance.Spec.Paused {
+			message = ovnv1.OVNNorthdPausingMessage
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNNorthdPausedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			message,
+			strings.Join(pending, ", ")))
+		result = ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}
+	} else if instance.Spec.Paused {
+		instance.Status.Conditions.MarkTrue(ovnv1.OVNNorthdPausedCondition, ovnv1.OVNNorthdPausedMessage)
+	} else {
+		instance.Status.Conditions.Remove(ovnv1.OVNNorthdPausedCondition)
+	}
+
+	// The SB lock moves between the replicas without any k8s event, it is polled
+	if *instance.Spec.Replicas > 1 && instance.Status.ReadyCount > 0 {
+		err = r.reconcileActiveInstance(ctx, instance, helper, serviceLabels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if result.RequeueAfter == 0 {
+			result = ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}
+		}
+	} else {
+		err = r.clearActiveInstance(ctx, instance, helper, serviceLabels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// nb_cfg moves with the NB changes without any k8s event, it is polled as well
+	if instance.Status.ReadyCount > 0 {
+		next := r.reconcileConfigPropagation(ctx, instance, helper)
+		if result.RequeueAfter == 0 || next < result.RequeueAfter {
+			result = ctrl.Result{RequeueAfter: next}
+		}
+	}
+
 	Log.Info("Reconciled Service successfully")
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// reconcilePause - pauses or resumes the running ovn-northd instances not in the state requested by the spec,
+// ready or not, returns the instances which could not be switched
+func (r *OVNNorthdReconciler) reconcilePause(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) ([]string, error) {
+	Log := r.GetLogger(ctx)
+
+	podList, err := helper.GetKClient().CoreV1().Pods(instance.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(serviceLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(podList.Items, func(i, j int) bool { return podList.Items[i].Name < podList.Items[j].Name })
+
+	pending := []string{}
+	for i := range podList.Items {
+		northdPod := &podList.Items[i]
+		if !ovnnorthd.IsRunning(*northdPod) {
+			continue
+		}
+		output, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, northdPod, ovnnorthd.ServiceName,
+			ovnnorthd.StatusCommand())
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to get the status of %s", northdPod.Name))
+			pending = append(pending, northdPod.Name)
+			continue
+		}
+		if (ovnnorthd.ParseStatus(output) == ovnnorthd.RolePaused) == instance.Spec.Paused {
+			continue
+		}
+
+		command := ovnnorthd.ResumeCommand()
+		if instance.Spec.Paused {
+			command = ovnnorthd.PauseCommand()
+		}
+		_, err = podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, northdPod, ovnnorthd.ServiceName, command)
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to pause or resume %s", northdPod.Name))
+			pending = append(pending, northdPod.Name)
+			continue
+		}
+		Log.Info(fmt.Sprintf("ovn-northd instance %s paused: %t", northdPod.Name, instance.Spec.Paused))
+	}
+	return pending, nil
 }
 
+// reconcileActiveInstance - records the role of each ovn-northd pod, labels the pod holding the SB lock as
+// active and emits an Event on failover
+func (r *OVNNorthdReconciler) reconcileActiveInstance(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) error {
+	Log := r.GetLogger(ctx)
+
+	podList, err := helper.GetKClient().CoreV1().Pods(instance.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(serviceLabels).String(),
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(podList.Items, func(i, j int) bool { return podList.Items[i].Name < podList.Items[j].Name })
+
+	active := ""
+	found := false
+	instances := []ovnv1.OVNNorthdInstance{}
+	for i := range podList.Items {
+		northdPod := &podList.Items[i]
+		if northdPod.Name == instance.Status.ActiveInstance {
+			found = true
+		}
+		if !ovndbcluster.IsPodReady(*northdPod) {
+			// the role is only known for the ready instances
+			err = r.patchRoleLabel(ctx, helper, northdPod, "")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		role := ovnnorthd.RoleUnknown
+		output, err := podexec.ExecInPod(ctx, helper.GetKClient(), r.RestConfig, northdPod, ovnnorthd.ServiceName,
+			ovnnorthd.StatusCommand())
+		if err != nil {
+			Log.Error(err, fmt.Sprintf("Unable to get the status of %s", northdPod.Name))
+		} else if status := ovnnorthd.ParseStatus(output); status != "" {
+			role = status
+		}
+		if role == ovnnorthd.RoleActive {
+			active = northdPod.Name
+		}
+		instances = append(instances, ovnv1.OVNNorthdInstance{Name: northdPod.Name, Role: role})
+
+		// a paused instance does not take over the lock, it is labeled as standby
+		label := ovnnorthd.RoleStandby
+		if role == ovnnorthd.RoleActive {
+			label = ovnnorthd.RoleActive
+		}
+		if role == ovnnorthd.RoleUnknown {
+			continue
+		}
+		err = r.patchRoleLabel(ctx, helper, northdPod, label)
+		if err != nil {
+			return err
+		}
+	}
+	instance.Status.Instances = instances
+
+	// no instance holds the lock while it moves, the previous one is kept unless its pod is gone
+	if active == "" {
+		if !found && instance.Status.ActiveInstance != "" {
+			Log.Info(fmt.Sprintf("Active ovn-northd instance %s is gone", instance.Status.ActiveInstance))
+			instance.Status.ActiveInstance = ""
+		}
+		return nil
+	}
+	if active == instance.Status.ActiveInstance {
+		return nil
+	}
+	if instance.Status.ActiveInstance != "" {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ActiveInstanceChanged",
+			"ovn-northd failed over from %s to %s", instance.Status.ActiveInstance, active)
+	}
+	Log.Info(fmt.Sprintf("Active ovn-northd instance is %s", active))
+	instance.Status.ActiveInstance = active
+	return nil
+}
+
+// clearActiveInstance - forgets the roles recorded by reconcileActiveInstance while the SB lock is not polled,
+// with a single replica or none ready
+func (r *OVNNorthdReconciler) clearActiveInstance(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) error {
+	instance.Status.ActiveInstance = ""
+	instance.Status.Instances = nil
+
+	podList, err := helper.GetKClient().CoreV1().Pods(instance.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(serviceLabels).String(),
+	})
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		err = r.patchRoleLabel(ctx, helper, &podList.Items[i], "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchRoleLabel - sets the role label of an ovn-northd pod, an empty role removes it
+func (r *OVNNorthdReconciler) patchRoleLabel(
+	ctx context.Context,
+	helper *helper.Helper,
+	northdPod *corev1.Pod,
+	role string,
+) error {
+	if northdPod.Labels[ovnnorthd.RoleLabel] == role {
+		return nil
+	}
+	patch := client.MergeFrom(northdPod.DeepCopy())
+	if role == "" {
+		delete(northdPod.Labels, ovnnorthd.RoleLabel)
+	} else {
+		if northdPod.Labels == nil {
+			northdPod.Labels = map[string]string{}
+		}
+		northdPod.Labels[ovnnorthd.RoleLabel] = role
+	}
+	err := helper.GetClient().Patch(ctx, northdPod, patch)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileTuningOptions - stores the tuning options read by ovn-northd in the NB_Global table, through the
+// same OVSDB client as the OVNGlobalConfig options. Options also listed by an OVNGlobalConfig of the NB
+// OVNDBCluster are left to it. The instances keep running with the previous values while the NB OVNDBCluster
+// is not ready.
+func (r *OVNNorthdReconciler) reconcileTuningOptions(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+) error {
+	Log := r.GetLogger(ctx)
+
+	options := ovnnorthd.TuningOptions(instance)
+	if len(options) == 0 {
+		return nil
+	}
+	cluster, err := ovnv1.GetDBClusterForRef(ctx, helper, instance.Spec.NBClusterRef, instance.Namespace, ovnv1.NBDBType)
+	if err != nil || !cluster.IsReady() {
+		return nil
+	}
+
+	globalConfigs := &ovnv1.OVNGlobalConfigList{}
+	if err := r.Client.List(ctx, globalConfigs); err != nil {
+		return err
+	}
+	for _, globalConfig := range globalConfigs.Items {
+		if !globalConfig.ConsumesDBCluster(cluster) {
+			continue
+		}
+		for key := range globalConfig.Spec.Options {
+			if _, ok := options[key]; ok {
+				Log.Info(fmt.Sprintf("NB_Global option %s is set by OVNGlobalConfig %s/%s instead of the tuning",
+					key, globalConfig.Namespace, globalConfig.Name))
+				delete(options, key)
+			}
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	nbClient, err := ovndbcluster.Connect(ctx, helper, cluster, time.Duration(5)*time.Second)
+	if err != nil {
+		return err
+	}
+	defer nbClient.Close()
+
+	nbGlobal, err := ovnglobalconfig.GetNBGlobal(nbClient)
+	if err != nil {
+		return err
+	}
+	changes := ovnglobalconfig.Changes{Set: map[string]string{}}
+	for key, value := range options {
+		if current, ok := nbGlobal.Options[key]; !ok || current != value {
+			changes.Set[key] = value
+		}
+	}
+	return ovnglobalconfig.Apply(nbClient, nbGlobal, changes)
+}
+
+// reconcileConfigPropagation - records how far the NB configuration got from the NB_Global sequence numbers
+// and sets the ConfigLagging condition when the SB database or the chassis stay behind for longer than the
+// threshold. It is checked while the NB OVNDBCluster is ready, failures are only logged. Returns when to check
+// again.
+func (r *OVNNorthdReconciler) reconcileConfigPropagation(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+) time.Duration {
+	Log := r.GetLogger(ctx)
+	threshold := time.Duration(instance.Spec.ConfigLagThreshold) * time.Second
+
+	cluster, err := ovnv1.GetDBClusterForRef(ctx, helper, instance.Spec.NBClusterRef, instance.Namespace, ovnv1.NBDBType)
+	if err != nil || !cluster.IsReady() {
+		return time.Duration(60) * time.Second
+	}
+	nbClient, err := ovndbcluster.Connect(ctx, helper, cluster, time.Duration(5)*time.Second)
+	if err != nil {
+		Log.Info(fmt.Sprintf("Unable to check the config propagation: %s", err.Error()))
+		return time.Duration(60) * time.Second
+	}
+	defer nbClient.Close()
+	cfg, err := propagation.GetNBGlobalCfg(nbClient)
+	if err != nil {
+		Log.Info(fmt.Sprintf("Unable to check the config propagation: %s", err.Error()))
+		return time.Duration(60) * time.Second
+	}
+
+	previous := instance.Status.ConfigPropagation
+	if previous == nil {
+		previous = &ovnv1.OVNNorthdConfigPropagation{}
+	}
+	now := metav1.Now()
+	status := &ovnv1.OVNNorthdConfigPropagation{
+		NBCfg:         cfg.NBCfg,
+		SBCfg:         cfg.SBCfg,
+		HVCfg:         cfg.HVCfg,
+		SBLag:         propagation.Track(previous.SBLag, cfg.NBCfg, cfg.SBCfg, now),
+		HVLag:         propagation.Track(previous.HVLag, cfg.NBCfg, cfg.HVCfg, now),
+		LastCheckTime: now,
+	}
+	instance.Status.ConfigPropagation = status
+
+	lagging := []string{}
+	if propagation.Exceeds(status.SBLag, threshold, now) {
+		lagging = append(lagging, fmt.Sprintf("SB database behind nb_cfg %d since %s",
+			status.SBLag.NBCfg, status.SBLag.Since.UTC().Format(time.RFC3339)))
+	}
+	if propagation.Exceeds(status.HVLag, threshold, now) {
+		lagging = append(lagging, fmt.Sprintf("chassis behind nb_cfg %d since %s",
+			status.HVLag.NBCfg, status.HVLag.Since.UTC().Format(time.RFC3339)))
+	}
+	if len(lagging) > 0 {
+		Log.Info(fmt.Sprintf("NB configuration not propagated: %s", strings.Join(lagging, ", ")))
+		instance.Status.Conditions.Set(condition.TrueCondition(
+			ovnv1.OVNConfigLaggingCondition,
+			ovnv1.OVNConfigLaggingMessage,
+			strings.Join(lagging, ", ")))
+	} else {
+		instance.Status.Conditions.Remove(ovnv1.OVNConfigLaggingCondition)
+	}
+
+	return propagation.NextCheck([]*ovnv1.OVNConfigLag{status.SBLag, status.HVLag}, threshold, now)
+}
+
+// generateScriptsConfigMap - creates the ConfigMap holding the metrics exporter
+func (r *OVNNorthdReconciler) generateScriptsConfigMap(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNNorthd,
+	envVars *map[string]env.Setter,
+) error {
+	cmLabels := common_labels.GetLabels(instance, common_labels.GetGroupLabel(ovnnorthd.ServiceName), map[string]string{})
+
+	templateParameters := make(map[string]interface{})
+	templateParameters["METRICS_PORT"] = instance.Spec.Metrics.Port
+	templateParameters["OVN_RUNDIR"] = ovnnorthd.RunDir
+	cms := []util.Template{
+		// ScriptsConfigMap
+		{
+			Name:          ovnnorthd.ScriptsConfigMapName(instance),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeScripts,
+			InstanceType:  instance.Kind,
+			Labels:        cmLabels,
+			ConfigOptions: templateParameters,
+		},
+	}
+	return configmap.EnsureConfigMaps(ctx, h, instance, cms, envVars)
+}
+
+// generateCABundleConfigMap - creates the ConfigMap holding the CA verifying the ovsdb-servers, the CA of the
+// client certificate and the CAs of the external NB and SB databases serving SSL
+func (r *OVNNorthdReconciler) generateCABundleConfigMap(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNNorthd,
+	envVars *map[string]env.Setter,
+) error {
+	tlsSecret, _, err := secret.GetSecret(ctx, h, instance.Spec.TLS.SecretName, instance.Namespace)
+	if err != nil {
+		return err
+	}
+	bundle := strings.TrimSpace(string(tlsSecret.Data["ca.crt"])) + "\n"
+	refs := map[string]*ovnv1.OVNDBClusterRef{
+		ovnv1.NBDBType: instance.Spec.NBClusterRef,
+		ovnv1.SBDBType: instance.Spec.SBClusterRef,
+	}
+	for _, dbType := range []string{ovnv1.NBDBType, ovnv1.SBDBType} {
+		cluster, err := ovnv1.GetDBClusterForRef(ctx, h, refs[dbType], instance.Namespace, dbType)
+		if err != nil {
+			// a missing cluster is reported by the NBDBReady and SBDBReady conditions
+			continue
+		}
+		externalCA, err := ovndbcluster.ExternalCA(ctx, h, cluster)
+		if err != nil {
+			return err
+		}
+		if len(externalCA) > 0 {
+			bundle += strings.TrimSpace(string(externalCA)) + "\n"
+		}
+	}
+
+	cmLabels := common_labels.GetLabels(instance, common_labels.GetGroupLabel(ovnnorthd.ServiceName), map[string]string{})
+	cms := []util.Template{
+		{
+			Name:         ovnnorthd.CABundleConfigMapName(instance),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       cmLabels,
+			CustomData:   map[string]string{ovnnorthd.CABundleKey: bundle},
+		},
+	}
+	return configmap.EnsureConfigMaps(ctx, h, instance, cms, envVars)
+}
+
+// generatePauseConfigMap - stores the pause state read by the ovn-northd pods starting
+func (r *OVNNorthdReconciler) generatePauseConfigMap(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNNorthd,
+) error {
+	cmLabels := common_labels.GetLabels(instance, common_labels.GetGroupLabel(ovnnorthd.ServiceName), map[string]string{})
+
+	cms := []util.Template{
+		{
+			Name:         ovnnorthd.PauseConfigMapName(instance),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       cmLabels,
+			CustomData:   map[string]string{ovnnorthd.PausedKey: fmt.Sprintf("%t", instance.Spec.Paused)},
+		},
+	}
+	return configmap.EnsureConfigMaps(ctx, h, instance, cms, &map[string]env.Setter{})
+}
+
+// reconcileMetrics - creates the Service of the metrics exporters and, if the Prometheus operator is
+// installed, the ServiceMonitor scraping it. Both are removed once the exporter is disabled.
+func (r *OVNNorthdReconciler) reconcileMetrics(
+	ctx context.Context,
+	instance *ovnv1.OVNNorthd,
+	helper *helper.Helper,
+	serviceLabels map[string]string,
+) error {
+	Log := r.GetLogger(ctx)
+
+	metricsLabels := map[string]string{
+		common.AppSelector: ovnnorthd.MetricsServiceName,
+	}
+	monitor := ovnnorthd.ServiceMonitor(instance, metricsLabels)
+
+	if !instance.Spec.Metrics.Enabled {
+		err := service.DeleteServicesWithLabel(ctx, helper, instance, metricsLabels)
+		if err != nil {
+			return err
+		}
+		err = r.Client.Delete(ctx, monitor)
+		if err != nil && !k8s_errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	svc, err := service.NewService(
+		ovnnorthd.MetricsService(instance, metricsLabels, serviceLabels),
+		time.Duration(5)*time.Second,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = svc.CreateOrPatch(ctx, helper)
+	if err != nil {
+		return err
+	}
+
+	desired := monitor.DeepCopy()
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, monitor, func() error {
+		monitor.SetLabels(desired.GetLabels())
+		monitor.Object["spec"] = desired.Object["spec"]
+		return controllerutil.SetControllerReference(instance, monitor, r.Scheme)
+	})
+	if meta.IsNoMatchError(err) {
+		Log.Info("ServiceMonitor kind not available, the metrics are not scraped automatically")
+		return nil
+	}
+	return err
+}
+
+// getInternalEndpoint - returns the internal endpoint of the NB or SB OVNDBCluster and reflects its availability
+// in the NBDBReady or SBDBReady condition. The endpoint is empty while the cluster is missing or not published
+// yet, which is not an error. A published cluster which is not ready, e.g. without quorum, is reported but its
+// endpoint is still returned, the instances keep running and reconnect on their own. The remotes are sorted so a
+// reordered list doesn't roll out the Deployment.
 func getInternalEndpoint(
 	ctx context.Context,
 	h *helper.Helper,
 	instance *ovnv1.OVNNorthd,
 	dbType string,
 ) (string, error) {
-	cluster, err := ovnv1.GetDBClusterByType(ctx, h, instance.Namespace, map[string]string{}, dbType)
+	ref := instance.Spec.SBClusterRef
+	conditionType := ovnv1.OVNNorthdSBDBReadyCondition
+	if dbType == ovnv1.NBDBType {
+		ref = instance.Spec.NBClusterRef
+		conditionType = ovnv1.OVNNorthdNBDBReadyCondition
+	}
+	clusterName := fmt.Sprintf("in namespace %s", instance.Namespace)
+	if ref != nil {
+		clusterName = fmt.Sprintf("%s/%s", ref.GetNamespace(instance.Namespace), ref.Name)
+	}
+
+	cluster, err := ovnv1.GetDBClusterForRef(ctx, h, ref, instance.Namespace, dbType)
 	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				conditionType,
+				condition.RequestedReason,
+				condition.SeverityInfo,
+				ovnv1.OVNDBClusterMissingMessage,
+				dbType,
+				clusterName))
+			return "", nil
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			conditionType,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			ovnv1.OVNDBClusterErrorMessage,
+			dbType,
+			clusterName,
+			err.Error()))
 		return "", err
 	}
+	clusterName = fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
+
 	internalEndpoint, err := cluster.GetInternalEndpoint()
 	if err != nil {
-		return "", err
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			conditionType,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			ovnv1.OVNDBClusterWaitingMessage,
+			dbType,
+			clusterName))
+		return "", nil
+	}
+	if cluster.IsReady() {
+		instance.Status.Conditions.MarkTrue(conditionType, ovnv1.OVNDBClusterReadyMessage, dbType, clusterName)
+	} else {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			conditionType,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			ovnv1.OVNDBClusterWaitingMessage,
+			dbType,
+			clusterName))
 	}
-	return internalEndpoint, nil
+
+	remotes := strings.Split(internalEndpoint, ",")
+	sort.Strings(remotes)
+	return strings.Join(remotes, ","), nil
 }
@@ -0,0 +1,448 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/lib-common/modules/common"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/labels"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovntrace"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// OVNTraceReconciler reconciles a OVNTrace object
+type OVNTraceReconciler struct {
+	client.Client
+	Kclient  kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// GetClient -
+func (r *OVNTraceReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetScheme -
+func (r *OVNTraceReconciler) GetScheme() *runtime.Scheme {
+	return r.Scheme
+}
+
+// GetLogger returns a logger object with a prefix of "controller.name" and additional controller context fields
+func (r *OVNTraceReconciler) GetLogger(ctx context.Context) logr.Logger {
+	return log.FromContext(ctx).WithName("Controllers").WithName("OVNTrace")
+}
+
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovntraces,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovntraces/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovntraces/finalizers,verbs=update
+//+kubebuilder:rbac:groups=ovn.openstack.org,resources=ovndbclusters,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=pods/log,verbs=get;
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch;update;delete;
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile - OVN trace
+func (r *OVNTraceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, _err error) {
+	Log := r.GetLogger(ctx)
+
+	// Fetch the OVNTrace instance
+	instance := &ovnv1.OVNTrace{}
+	err := r.Client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+	//
+	// initialize status
+	//
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		// initialize conditions used later as Status=Unknown
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.InputReadyCondition, condition.InitReason, condition.InputReadyInitMessage),
+			condition.UnknownCondition(condition.ServiceConfigReadyCondition, condition.InitReason, condition.ServiceConfigReadyInitMessage),
+			condition.UnknownCondition(ovnv1.OVNTraceCompletedCondition, condition.InitReason, ovnv1.OVNTraceCompletedInitMessage),
+		)
+
+		instance.Status.Conditions.Init(&cl)
+		instance.Status.Phase = ovnv1.TracePhasePending
+
+		// Register overall status immediately to have an early feedback e.g. in the cli
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	// a new generation of the spec is traced again
+	if instance.Status.ObservedGeneration != instance.Generation {
+		instance.Status.Phase = ovnv1.TracePhasePending
+		instance.Status.CompletionTime = nil
+		delete(instance.Status.Hash, "trace")
+		instance.Status.Conditions.MarkUnknown(
+			ovnv1.OVNTraceCompletedCondition, condition.InitReason, ovnv1.OVNTraceCompletedInitMessage)
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+
+	helper, err := helper.NewHelper(
+		instance,
+		r.Client,
+		r.Kclient,
+		r.Scheme,
+		Log,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the instance status when exiting this function so we can persist any changes.
+	defer func() {
+		// update the Ready condition based on the sub conditions
+		if instance.Status.Conditions.AllSubConditionIsTrue() {
+			instance.Status.Conditions.MarkTrue(
+				condition.ReadyCondition, condition.ReadyMessage)
+		} else {
+			// something is not ready so reset the Ready condition
+			instance.Status.Conditions.MarkUnknown(
+				condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage)
+			// and recalculate it based on the state of the rest of the conditions
+			instance.Status.Conditions.Set(
+				instance.Status.Conditions.Mirror(condition.ReadyCondition))
+		}
+		err := helper.PatchInstance(ctx, instance)
+		if err != nil {
+			_err = err
+			return
+		}
+	}()
+
+	// Nothing owned needs cleanup beyond the garbage collection, and a finished trace is final
+	if !instance.DeletionTimestamp.IsZero() ||
+		instance.Status.Phase == ovnv1.TracePhaseCompleted ||
+		instance.Status.Phase == ovnv1.TracePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, instance, helper)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OVNTraceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ovnv1.OVNTrace{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &ovnv1.OVNDBCluster{}}, handler.EnqueueRequestsFromMapFunc(r.sbClusterMapFunc)).
+		Complete(r)
+}
+
+// sbClusterMapFunc - enqueues the pending OVNTraces waiting for an SB OVNDBCluster
+func (r *OVNTraceReconciler) sbClusterMapFunc(obj client.Object) []reconcile.Request {
+	result := []reconcile.Request{}
+
+	cluster, ok := obj.(*ovnv1.OVNDBCluster)
+	if !ok || cluster.Spec.DBType != ovnv1.SBDBType {
+		return nil
+	}
+	traces := &ovnv1.OVNTraceList{}
+	if err := r.Client.List(context.Background(), traces); err != nil {
+		r.GetLogger(context.Background()).Error(err, "Unable to retrieve OVNTraces")
+		return nil
+	}
+	for _, trace := range traces.Items {
+		if trace.Status.Phase != ovnv1.TracePhasePending {
+			continue
+		}
+		if trace.Spec.SBClusterRef.RefersTo(cluster, trace.Namespace) {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&trace)})
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	return nil
+}
+
+func (r *OVNTraceReconciler) reconcileNormal(ctx context.Context, instance *ovnv1.OVNTrace, helper *helper.Helper) (ctrl.Result, error) {
+	Log := r.GetLogger(ctx)
+
+	Log.Info("Reconciling OVN trace")
+
+	// an invalid spec waits for the next generation
+	if err := instance.ValidateFlow(); err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.InputReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, nil
+	}
+
+	sbCluster, err := ovnv1.GetDBClusterForRef(ctx, helper, instance.Spec.SBClusterRef, instance.Namespace, ovnv1.SBDBType)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	var sbEndpoint string
+	if err == nil {
+		sbEndpoint, err = sbCluster.GetInternalEndpoint()
+	}
+	if err != nil {
+		Log.Info(fmt.Sprintf("SB OVNDBCluster not ready yet: %s", err.Error()))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.InputReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			condition.InputReadyWaitingMessage))
+		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	}
+	instance.Status.Conditions.MarkTrue(condition.InputReadyCondition, condition.InputReadyMessage)
+
+	clientCert, err := r.reconcileTLSSecret(ctx, instance, sbCluster)
+	if err == nil {
+		err = r.generateServiceConfigMaps(ctx, helper, instance, sbCluster, sbEndpoint, clientCert)
+	}
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ServiceConfigReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			condition.ServiceConfigReadyErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.Conditions.MarkTrue(condition.ServiceConfigReadyCondition, condition.ServiceConfigReadyMessage)
+
+	if instance.Status.Phase == ovnv1.TracePhasePending {
+		instance.Status.Phase = ovnv1.TracePhaseRunning
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNTraceCompletedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			ovnv1.OVNTraceRunningMessage))
+	}
+
+	serviceLabels := map[string]string{
+		common.AppSelector: "ovntrace",
+		"ovntrace":         instance.Name,
+	}
+	jobName := ovntrace.JobName(instance)
+	traceJob := job.NewJob(
+		ovntrace.TraceJob(instance, sbCluster, serviceLabels),
+		"trace",
+		false,
+		time.Duration(5)*time.Second,
+		instance.Status.Hash["trace"],
+	)
+	ctrlResult, err := traceJob.DoJob(ctx, helper)
+	if err != nil && k8s_errors.IsInternalError(err) {
+		// DoJob reports a failed job as an internal error, its log tells why
+		Log.Info(fmt.Sprintf("OVN trace job %s failed", jobName))
+		if err := r.storeResult(ctx, helper, instance, jobName); err != nil {
+			Log.Info(fmt.Sprintf("No output of OVN trace job %s: %s", jobName, err.Error()))
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "TraceFailed", "Trace by job %s failed", jobName)
+		instance.Status.Phase = ovnv1.TracePhaseFailed
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNTraceCompletedCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			ovnv1.OVNTraceErrorMessage,
+			fmt.Sprintf("job %s failed", jobName)))
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+	if !traceJob.HasChanged() {
+		return ctrl.Result{}, nil
+	}
+
+	err = r.storeResult(ctx, helper, instance, jobName)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			ovnv1.OVNTraceCompletedCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			ovnv1.OVNTraceErrorMessage,
+			err.Error()))
+		return ctrl.Result{}, err
+	}
+	instance.Status.Hash["trace"] = traceJob.GetHash()
+	now := metav1.Now()
+	instance.Status.CompletionTime = &now
+	instance.Status.Phase = ovnv1.TracePhaseCompleted
+	instance.Status.Conditions.MarkTrue(
+		ovnv1.OVNTraceCompletedCondition, ovnv1.OVNTraceCompletedMessage, instance.Status.ResultConfigMap)
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "TraceSucceeded",
+		"Trace stored in ConfigMap %s", instance.Status.ResultConfigMap)
+
+	Log.Info("Reconciled OVN trace successfully")
+	return ctrl.Result{}, nil
+}
+
+// reconcileTLSSecret - copies the client certificate of the SB OVNDBCluster into a Secret of the OVNTrace, the
+// job can't mount the Secret of a cluster in another namespace. Returns false if the Secret only holds the CA,
+// which is the case for external databases.
+func (r *OVNTraceReconciler) reconcileTLSSecret(
+	ctx context.Context,
+	instance *ovnv1.OVNTrace,
+	sbCluster *ovnv1.OVNDBCluster,
+) (bool, error) {
+	if !sbCluster.IsTLSEnabled() {
+		return false, nil
+	}
+	clusterSecret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: sbCluster.Namespace, Name: sbCluster.Spec.TLS.SecretName}, clusterSecret)
+	if err != nil {
+		return false, err
+	}
+
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ovntrace.TLSSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, tlsSecret, func() error {
+		tlsSecret.Labels = labels.GetLabels(instance, labels.GetGroupLabel("ovntrace"), map[string]string{})
+		tlsSecret.Data = map[string][]byte{}
+		for _, key := range []string{"tls.crt", "tls.key", "ca.crt"} {
+			if value, ok := clusterSecret.Data[key]; ok {
+				tlsSecret.Data[key] = value
+			}
+		}
+		return controllerutil.SetControllerReference(instance, tlsSecret, r.Scheme)
+	})
+	return len(clusterSecret.Data["tls.crt"]) > 0, err
+}
+
+// storeResult - copies the log of the finished job pod, the output of ovn-trace, into the result ConfigMap
+func (r *OVNTraceReconciler) storeResult(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNTrace,
+	jobName string,
+) error {
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels{"job-name": jobName})
+	if err != nil {
+		return err
+	}
+	podName := ""
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			podName = pod.Name
+		}
+	}
+	if podName == "" {
+		return fmt.Errorf("no finished pod of job %s found", jobName)
+	}
+
+	limitBytes := int64(ovntrace.MaxResultBytes)
+	output, err := r.Kclient.CoreV1().Pods(instance.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  ovntrace.ContainerName,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+
+	cms := []util.Template{
+		{
+			Name:         ovntrace.ResultConfigMapName(instance),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       labels.GetLabels(instance, labels.GetGroupLabel("ovntrace"), map[string]string{}),
+			CustomData:   map[string]string{ovntrace.ResultKey: string(output)},
+		},
+	}
+	err = configmap.EnsureConfigMaps(ctx, h, instance, cms, &map[string]env.Setter{})
+	if err != nil {
+		return err
+	}
+	instance.Status.ResultConfigMap = ovntrace.ResultConfigMapName(instance)
+	return nil
+}
+
+func (r *OVNTraceReconciler) generateServiceConfigMaps(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *ovnv1.OVNTrace,
+	sbCluster *ovnv1.OVNDBCluster,
+	sbEndpoint string,
+	clientCert bool,
+) error {
+	cmLabels := labels.GetLabels(instance, labels.GetGroupLabel("ovntrace"), map[string]string{})
+
+	templateParameters := make(map[string]interface{})
+	templateParameters["SB"] = sbEndpoint
+	templateParameters["TLS"] = sbCluster.IsTLSEnabled()
+	templateParameters["TLS_PATH"] = ovntrace.TLSPath
+	templateParameters["CLIENT_CERT"] = clientCert
+	templateParameters["SB_TIMEOUT"] = ovntrace.SBTimeout
+	cms := []util.Template{
+		// ScriptsConfigMap
+		{
+			Name:          fmt.Sprintf("%s-scripts", instance.Name),
+			Namespace:     instance.Namespace,
+			Type:          util.TemplateTypeScripts,
+			InstanceType:  instance.Kind,
+			Labels:        cmLabels,
+			ConfigOptions: templateParameters,
+		},
+	}
+	return configmap.EnsureConfigMaps(ctx, h, instance, cms, &map[string]env.Setter{})
+}
@@ -106,29 +106,62 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.OVNNorthdReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Kclient: kclient,
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Kclient:    kclient,
+		Recorder:   mgr.GetEventRecorderFor("ovnnorthd-controller"),
+		RestConfig: cfg,
 	}).SetupWithManager(mgr, context.Background()); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OVNNorthd")
 		os.Exit(1)
 	}
 	if err = (&controllers.OVNDBClusterReconciler{
-		Client:  mgr.GetClient(),
-		Kclient: kclient,
-		Scheme:  mgr.GetScheme(),
+		Client:     mgr.GetClient(),
+		Kclient:    kclient,
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("ovndbcluster-controller"),
+		RestConfig: cfg,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OVNDBCluster")
 		os.Exit(1)
 	}
 	if err = (&controllers.OVNControllerReconciler{
-		Client:  mgr.GetClient(),
-		Kclient: kclient,
-		Scheme:  mgr.GetScheme(),
+		Client:     mgr.GetClient(),
+		Kclient:    kclient,
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("ovncontroller-controller"),
+		RestConfig: cfg,
 	}).SetupWithManager(mgr, context.Background()); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OVNController")
 		os.Exit(1)
 	}
+	if err = (&controllers.OVNDBMigrationReconciler{
+		Client:   mgr.GetClient(),
+		Kclient:  kclient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ovndbmigration-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNDBMigration")
+		os.Exit(1)
+	}
+	if err = (&controllers.OVNGlobalConfigReconciler{
+		Client:   mgr.GetClient(),
+		Kclient:  kclient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ovnglobalconfig-controller"),
+	}).SetupWithManager(mgr, context.Background()); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNGlobalConfig")
+		os.Exit(1)
+	}
+	if err = (&controllers.OVNTraceReconciler{
+		Client:   mgr.GetClient(),
+		Kclient:  kclient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ovntrace-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNTrace")
+		os.Exit(1)
+	}
 
 	// Acquire environmental defaults and initialize operator defaults with them
 	ovnv1.SetupDefaults()
@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovncontroller
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+)
+
+const (
+	// ChassisCertValidity - lifetime of the per-chassis client certificates
+	ChassisCertValidity = 365 * 24 * time.Hour
+
+	// ChassisCertRenewBefore - a per-chassis client certificate expiring sooner is issued again
+	ChassisCertRenewBefore = 30 * 24 * time.Hour
+
+	// ChassisCertLabel - label holding the OVNController name on the per-chassis certificate Secrets
+	ChassisCertLabel = "ovn-chassis-cert"
+
+	// ChassisNodeLabel - label holding the node name on the per-chassis certificate Secrets
+	ChassisNodeLabel = "ovn-chassis-node"
+)
+
+// ChassisCertSecretName - name of the Secret holding the client certificate of the chassis running on the node
+func ChassisCertSecretName(instance *v1beta1.OVNController, nodeName string) string {
+	return fmt.Sprintf("%s-chassis-%s", instance.Name, nodeName)
+}
+
+// SystemIDCommand - command run in the ovsdb-server container to read the system-id of the chassis
+func SystemIDCommand() []string {
+	return []string{"ovs-vsctl", "--if-exists", "get", "open", ".", "external_ids:system-id"}
+}
+
+// ParseSystemID - system-id from the output of SystemIDCommand, empty if not set yet
+func ParseSystemID(output string) string {
+	return strings.Trim(strings.TrimSpace(output), "\"")
+}
+
+// ChassisCertValid - true if the Secret data holds a certificate for the system-id, issued by the
+// current CA and not about to expire
+func ChassisCertValid(data map[string][]byte, caCert []byte, systemID string, now time.Time) bool {
+	if len(data["tls.key"]) == 0 {
+		return false
+	}
+	cert, err := parseCert(data["tls.crt"])
+	if err != nil {
+		return false
+	}
+	ca, err := parseCert(caCert)
+	if err != nil || cert.CheckSignatureFrom(ca) != nil {
+		return false
+	}
+	return cert.Subject.CommonName == systemID && now.Add(ChassisCertRenewBefore).Before(cert.NotAfter)
+}
+
+// ChassisCABundle - CA certificates the chassis verifies the SB ovsdb-servers with, the CA issuing the
+// per-chassis certificates and the CA of an external SB database serving SSL
+func ChassisCABundle(caCert []byte, externalCA []byte) []byte {
+	bundle := append(append([]byte{}, bytes.TrimSpace(caCert)...), '\n')
+	if len(externalCA) > 0 {
+		bundle = append(append(bundle, bytes.TrimSpace(externalCA)...), '\n')
+	}
+	return bundle
+}
+
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// IssueChassisCert - issues a client certificate with the system-id as CN, signed by the CA
+// (tls.crt, tls.key) of caData. Returns the Secret data holding tls.crt and tls.key.
+func IssueChassisCert(caData map[string][]byte, systemID string, now time.Time) (map[string][]byte, error) {
+	ca, err := tls.X509KeyPair(caData["tls.crt"], caData["tls.key"])
+	if err != nil {
+		return nil, fmt.Errorf("error loading the CA: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the CA certificate: %w", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: systemID},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(ChassisCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing the certificate of chassis %s: %w", systemID, err)
+	}
+
+	return map[string][]byte{
+		"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		"tls.key": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
@@ -34,6 +34,7 @@ func ConfigJob(
 	instance *v1beta1.OVNController,
 	sbCluster *v1beta1.OVNDBCluster,
 	labels map[string]string,
+	certHashes map[string]string,
 ) ([]*batchv1.Job, error) {
 
 	var jobs []*batchv1.Job
@@ -52,7 +53,7 @@ func ConfigJob(
 		return nil, err
 	}
 
-	internalEndpoint, err := sbCluster.GetInternalEndpoint()
+	internalEndpoint, err := sbCluster.GetInternalChassisEndpoint()
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +65,22 @@ func ConfigJob(
 	envVars["EnableChassisAsGateway"] = env.SetValue(fmt.Sprintf("%t", instance.Spec.ExternalIDS.EnableChassisAsGateway))
 	envVars["PhysicalNetworks"] = env.SetValue(getPhysicalNetworks(instance))
 	envVars["OvnHostName"] = EnvDownwardAPI("spec.nodeName")
+	if instance.IsTLSEnabled() {
+		envVars["OvnTLS"] = env.SetValue("true")
+		envVars["OvnPKIDir"] = env.SetValue(OvnPKIDir)
+		envVars["OvnCertDir"] = env.SetValue(OvnCertDir)
+	}
 
 	for _, ovnPod := range ovnPods.Items {
+		jobEnvVars := envVars
+		if instance.IsTLSEnabled() {
+			jobEnvVars = map[string]env.Setter{}
+			for k, v := range envVars {
+				jobEnvVars[k] = v
+			}
+			// the certificate hash re-runs the job of the node when its certificate is issued again
+			jobEnvVars["OvnCertHash"] = env.SetValue(certHashes[ovnPod.Spec.NodeName])
+		}
 		jobs = append(
 			jobs,
 			&batchv1.Job{
@@ -92,12 +107,12 @@ func ConfigJob(
 										RunAsUser:  &runAsUser,
 										Privileged: &privileged,
 									},
-									Env:          env.MergeEnvs([]corev1.EnvVar{}, envVars),
-									VolumeMounts: GetOvnControllerVolumeMounts(),
+									Env:          env.MergeEnvs([]corev1.EnvVar{}, jobEnvVars),
+									VolumeMounts: append(GetOvnControllerVolumeMounts(), GetConfigJobTLSVolumeMounts(instance)...),
 									Resources:    instance.Spec.Resources,
 								},
 							},
-							Volumes:  GetVolumes(instance.Name, instance.Namespace),
+							Volumes:  append(append(GetVolumes(instance.Name, instance.Namespace), GetTLSVolumes(instance)...), GetChassisCertVolumes(instance, ovnPod.Spec.NodeName)...),
 							NodeName: ovnPod.Spec.NodeName,
 						},
 					},
@@ -3,4 +3,10 @@ package ovncontroller
 const (
 	// ServiceName - ovn-controller service name
 	ServiceName = "ovn-controller"
+
+	// OvnPKIDir - directory holding the per-chassis client certificate
+	OvnPKIDir = "/etc/pki/ovn"
+
+	// OvnCertDir - directory the config job reads the per-chassis client certificate from
+	OvnCertDir = "/etc/pki/ovn-chassis"
 )
@@ -230,7 +230,7 @@ func DaemonSet(
 								Privileged: &privileged,
 							},
 							Env:                      env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:             GetOvnControllerVolumeMounts(),
+							VolumeMounts:             append(GetOvnControllerVolumeMounts(), GetTLSVolumeMounts(instance)...),
 							Resources:                instance.Spec.Resources,
 							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
@@ -239,7 +239,7 @@ func DaemonSet(
 			},
 		},
 	}
-	daemonset.Spec.Template.Spec.Volumes = GetVolumes(instance.Name, instance.Namespace)
+	daemonset.Spec.Template.Spec.Volumes = append(GetVolumes(instance.Name, instance.Namespace), GetTLSVolumes(instance)...)
 
 	if instance.Spec.NodeSelector != nil && len(instance.Spec.NodeSelector) > 0 {
 		daemonset.Spec.Template.Spec.NodeSelector = instance.Spec.NodeSelector
@@ -2,6 +2,8 @@ package ovncontroller
 
 import (
 	"fmt"
+
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -205,3 +207,76 @@ func GetOvnControllerVolumeMounts() []corev1.VolumeMount {
 		},
 	}
 }
+
+// GetTLSVolumes - host directory holding the per-chassis client certificate, empty if TLS is disabled
+func GetTLSVolumes(instance *v1beta1.OVNController) []corev1.Volume {
+	if !instance.IsTLSEnabled() {
+		return []corev1.Volume{}
+	}
+	directoryOrCreate := corev1.HostPathDirectoryOrCreate
+
+	return []corev1.Volume{
+		{
+			Name: "etc-pki-ovn",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: fmt.Sprintf("/var/home/core/%s/etc/pki/ovn", instance.Namespace),
+					Type: &directoryOrCreate,
+				},
+			},
+		},
+	}
+}
+
+// GetTLSVolumeMounts - per-chassis client certificate VolumeMounts, empty if TLS is disabled
+func GetTLSVolumeMounts(instance *v1beta1.OVNController) []corev1.VolumeMount {
+	if !instance.IsTLSEnabled() {
+		return []corev1.VolumeMount{}
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      "etc-pki-ovn",
+			MountPath: OvnPKIDir,
+			ReadOnly:  true,
+		},
+	}
+}
+
+// GetChassisCertVolumes - client certificate issued to the chassis of the node, used by its config job
+func GetChassisCertVolumes(instance *v1beta1.OVNController, nodeName string) []corev1.Volume {
+	if !instance.IsTLSEnabled() {
+		return []corev1.Volume{}
+	}
+	var certVolumeDefaultMode int32 = 0400
+
+	return []corev1.Volume{
+		{
+			Name: "ovn-chassis-cert",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  ChassisCertSecretName(instance, nodeName),
+					DefaultMode: &certVolumeDefaultMode,
+				},
+			},
+		},
+	}
+}
+
+// GetConfigJobTLSVolumeMounts - config job VolumeMounts to install the per-chassis client certificate
+func GetConfigJobTLSVolumeMounts(instance *v1beta1.OVNController) []corev1.VolumeMount {
+	if !instance.IsTLSEnabled() {
+		return []corev1.VolumeMount{}
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      "etc-pki-ovn",
+			MountPath: OvnPKIDir,
+			ReadOnly:  false,
+		},
+		{
+			Name:      "ovn-chassis-cert",
+			MountPath: OvnCertDir,
+			ReadOnly:  true,
+		},
+	}
+}
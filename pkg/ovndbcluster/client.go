@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovndbcluster
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovsdb"
+)
+
+// ClientTLSConfig - returns the TLS configuration to connect to the database, nil without TLS. The
+// certificate of the ovsdb-servers is accepted by their peers, it is a valid client certificate.
+func ClientTLSConfig(ctx context.Context, h *helper.Helper, cluster *ovnv1.OVNDBCluster) (*tls.Config, error) {
+	if !cluster.IsTLSEnabled() {
+		return nil, nil
+	}
+	tlsSecret, _, err := secret.GetSecret(ctx, h, cluster.Spec.TLS.SecretName, cluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return ExternalTLSConfig(tlsSecret.Data)
+}
+
+// Connect - connects to the database through its internal endpoint
+func Connect(
+	ctx context.Context,
+	h *helper.Helper,
+	cluster *ovnv1.OVNDBCluster,
+	timeout time.Duration,
+) (*ovsdb.Client, error) {
+	remotes, err := cluster.GetInternalRemotes()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := ClientTLSConfig(ctx, h, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return ovsdb.Dial(ctx, remotes, tlsConfig, timeout)
+}
+
+// ExternalCA - returns the ca.crt of an external database serving SSL, which its consumers have to trust on
+// top of the CA of the internal databases. Nil for the other clusters.
+func ExternalCA(ctx context.Context, h *helper.Helper, cluster *ovnv1.OVNDBCluster) ([]byte, error) {
+	if cluster.Spec.Mode != ovnv1.ExternalMode || !cluster.IsTLSEnabled() {
+		return nil, nil
+	}
+	ssl := false
+	for _, remote := range cluster.GetExternalRemotes() {
+		ssl = ssl || strings.HasPrefix(remote, "ssl:")
+	}
+	if !ssl {
+		return nil, nil
+	}
+	tlsSecret, _, err := secret.GetSecret(ctx, h, cluster.Spec.TLS.SecretName, cluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return tlsSecret.Data["ca.crt"], nil
+}
@@ -0,0 +1,115 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovndbcluster
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+)
+
+var (
+	serverIDRegexp = regexp.MustCompile(`(?m)^Server ID: ([0-9a-f]+) `)
+	addressRegexp  = regexp.MustCompile(`(?m)^Address: (\S+)`)
+	roleRegexp     = regexp.MustCompile(`(?m)^Role: (\S+)`)
+	statusRegexp   = regexp.MustCompile(`(?m)^Status: (.+)$`)
+	timerRegexp    = regexp.MustCompile(`(?m)^Election timer: ([0-9]+)`)
+	serverRegexp   = regexp.MustCompile(`(?m)^\s+([0-9a-f]+) \([0-9a-f]+ at ([^)]+)\)`)
+)
+
+// ClusterStatus - the parts of the ovsdb-server cluster/status output of a member used by the operator
+type ClusterStatus struct {
+	// ServerID - short server ID of the member
+	ServerID string
+	// Address - RAFT address of the member
+	Address string
+	// Role - leader, follower or candidate
+	Role string
+	// Status - e.g. "cluster member" or "joining cluster"
+	Status string
+	// ElectionTimer - election timer of the cluster in milliseconds
+	ElectionTimer int32
+	// Servers - RAFT address by short server ID of all the servers the member knows about
+	Servers map[string]string
+}
+
+// DBName - returns the schema name of the database
+func DBName(instance *ovnv1.OVNDBCluster) string {
+	if instance.Spec.DBType == ovnv1.SBDBType {
+		return "OVN_Southbound"
+	}
+	return "OVN_Northbound"
+}
+
+// CtlSocket - returns the unixctl socket of the ovsdb-server
+func CtlSocket(instance *ovnv1.OVNDBCluster) string {
+	return fmt.Sprintf("/tmp/ovn%s_db.ctl", strings.ToLower(instance.Spec.DBType))
+}
+
+// ClusterStatusCommand - returns the command printing the cluster/status of a member
+func ClusterStatusCommand(instance *ovnv1.OVNDBCluster) []string {
+	return []string{"ovs-appctl", "-t", CtlSocket(instance), "cluster/status", DBName(instance)}
+}
+
+// ClusterKickCommand - returns the command removing the server from the cluster
+func ClusterKickCommand(instance *ovnv1.OVNDBCluster, serverID string) []string {
+	return []string{"ovs-appctl", "-t", CtlSocket(instance), "cluster/kick", DBName(instance), serverID}
+}
+
+// ClusterTransferLeadershipCommand - returns the command making the leader step down and hand over the
+// leadership to the most up to date follower
+func ClusterTransferLeadershipCommand(instance *ovnv1.OVNDBCluster) []string {
+	return []string{"ovs-appctl", "-t", CtlSocket(instance), "cluster/failure-test", "transfer-leadership"}
+}
+
+// ClusterLeaveCommand - returns the command making the member leave the cluster. A leader transfers the
+// leadership before leaving.
+func ClusterLeaveCommand(instance *ovnv1.OVNDBCluster) []string {
+	return []string{"ovs-appctl", "-t", CtlSocket(instance), "cluster/leave", DBName(instance)}
+}
+
+// ParseClusterStatus - parses the cluster/status output of a member
+func ParseClusterStatus(output string) ClusterStatus {
+	status := ClusterStatus{
+		Servers: map[string]string{},
+	}
+	if m := serverIDRegexp.FindStringSubmatch(output); m != nil {
+		status.ServerID = m[1]
+	}
+	if m := addressRegexp.FindStringSubmatch(output); m != nil {
+		status.Address = m[1]
+	}
+	if m := roleRegexp.FindStringSubmatch(output); m != nil {
+		status.Role = m[1]
+	}
+	if m := statusRegexp.FindStringSubmatch(output); m != nil {
+		status.Status = strings.TrimSpace(m[1])
+	}
+	if m := timerRegexp.FindStringSubmatch(output); m != nil {
+		if timer, err := strconv.ParseInt(m[1], 10, 32); err == nil {
+			status.ElectionTimer = int32(timer)
+		}
+	}
+	if idx := strings.Index(output, "\nServers:\n"); idx >= 0 {
+		for _, m := range serverRegexp.FindAllStringSubmatch(output[idx:], -1) {
+			status.Servers[m[1]] = m[2]
+		}
+	}
+	return status
+}
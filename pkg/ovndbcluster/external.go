@@ -0,0 +1,96 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovndbcluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ExternalTLSConfig - returns the client TLS configuration to connect to an external database from the
+// data of its TLS Secret. The client certificate is optional.
+func ExternalTLSConfig(data map[string][]byte) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data["ca.crt"]) {
+		return nil, fmt.Errorf("no CA certificate found in ca.crt")
+	}
+	config := &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(data["tls.crt"]) > 0 {
+		cert, err := tls.X509KeyPair(data["tls.crt"], data["tls.key"])
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// CheckRemotes - connects to each of the OVSDB remotes, doing the TLS handshake for the ssl ones. The
+// database is reachable if at least one remote accepts the connection, as the clients fail over between
+// the remotes.
+func CheckRemotes(ctx context.Context, remotes []string, tlsConfig *tls.Config, timeout time.Duration) error {
+	errs := []string{}
+	for _, remote := range remotes {
+		err := checkRemote(ctx, remote, tlsConfig, timeout)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", remote, err.Error()))
+	}
+	return fmt.Errorf("%s", strings.Join(errs, ", "))
+}
+
+func checkRemote(ctx context.Context, remote string, tlsConfig *tls.Config, timeout time.Duration) error {
+	proto, address, found := strings.Cut(remote, ":")
+	if !found {
+		return fmt.Errorf("invalid remote")
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	switch proto {
+	case "tcp":
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case "ssl":
+		if tlsConfig == nil {
+			return fmt.Errorf("no TLS configuration")
+		}
+		config := tlsConfig.Clone()
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		config.ServerName = host
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: config}
+		conn, err := tlsDialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	return fmt.Errorf("unsupported protocol %s", proto)
+}
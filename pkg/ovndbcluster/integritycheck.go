@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovndbcluster
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IntegrityCheckCommand -
+	IntegrityCheckCommand = "/usr/local/bin/container-scripts/integrity_check.sh"
+)
+
+// IntegrityCheckJob - job checking a copy of the DB files of the member running in ovnPod.
+// The job runs on the node of the member to be able to mount its ReadWriteOnce PVC.
+func IntegrityCheckJob(
+	instance *ovnv1.OVNDBCluster,
+	ovnPod corev1.Pod,
+	labels map[string]string,
+	runID string,
+) *batchv1.Job {
+	pvcName := instance.Name + PvcSuffixEtcOvn
+	backoffLimit := int32(0)
+	// the result is recorded in the status, the job itself is not needed afterwards
+	jobTTLAfterFinished := int32(600)
+
+	envVars := map[string]env.Setter{}
+	// a new run id re-runs the job
+	envVars["INTEGRITY_CHECK_RUN"] = env.SetValue(runID)
+
+	volumes := append(
+		GetDBClusterVolumes(instance.Name),
+		corev1.Volume{
+			Name: pvcName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvcName + "-" + ovnPod.Name,
+					ReadOnly:  true,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "integrity-check",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ovnPod.Name + "-integrity-check",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &jobTTLAfterFinished,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: instance.RbacResourceName(),
+					Containers: []corev1.Container{
+						{
+							Name:    "integrity-check",
+							Image:   instance.Spec.ContainerImage,
+							Command: []string{"/bin/bash", "-c"},
+							Args:    []string{IntegrityCheckCommand},
+							Env:     env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scripts",
+									MountPath: "/usr/local/bin/container-scripts",
+									ReadOnly:  true,
+								},
+								{
+									Name:      pvcName,
+									MountPath: "/etc/ovn",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "integrity-check",
+									MountPath: "/var/lib/integrity-check",
+								},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes:  volumes,
+					NodeName: ovnPod.Spec.NodeName,
+				},
+			},
+		},
+	}
+}
+
+// HealCheckJob - integrity check job of a crashlooping member, deciding whether it gets rebuilt. It runs
+// independently from the periodic integrity check.
+func HealCheckJob(
+	instance *ovnv1.OVNDBCluster,
+	ovnPod corev1.Pod,
+	labels map[string]string,
+	runID string,
+) *batchv1.Job {
+	checkJob := IntegrityCheckJob(instance, ovnPod, labels, runID)
+	checkJob.Name = ovnPod.Name + "-heal-check"
+	return checkJob
+}
@@ -36,3 +36,40 @@ func OVNDBPods(
 	podSelectorString := k8s_labels.Set(serviceLabels).String()
 	return helper.GetKClient().CoreV1().Pods(instance.Namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelectorString})
 }
+
+// IsPodReady - returns true if the pod reports the Ready condition
+func IsPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsCrashLooping - returns true if the container restarted at least threshold times and is waiting in
+// CrashLoopBackOff
+func IsCrashLooping(pod corev1.Pod, containerName string, threshold int32) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.RestartCount >= threshold &&
+				status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff"
+		}
+	}
+	return false
+}
+
+// RestartCount - returns the restarts of the container
+func RestartCount(pod corev1.Pod, containerName string) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
+
+// PVCName - returns the name of the PersistentVolumeClaim of the member, created from the volume claim template
+func PVCName(instance *ovnv1.OVNDBCluster, podName string) string {
+	return instance.Name + PvcSuffixEtcOvn + "-" + podName
+}
@@ -1,12 +1,23 @@
 package ovndbcluster
 
 import (
+	"fmt"
+
 	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// RoleLabel - label the operator keeps on the members with their RAFT role
+	RoleLabel = "ovn-role"
+	// RoleLeader - RoleLabel value of the RAFT leader
+	RoleLeader = "leader"
+	// RoleFollower - RoleLabel value of the other members
+	RoleFollower = "follower"
+)
+
 // Service - Service for ovndbcluster per pod
 func Service(
 	serviceName string,
@@ -15,15 +26,15 @@ func Service(
 ) *corev1.Service {
 	dbPortName := "north"
 	raftPortName := "north-raft"
-	var dbPort int32 = 6641
-	var raftPort int32 = 6643
+	var dbPort int32 = v1beta1.DBPortNB
+	var raftPort int32 = v1beta1.RaftPortNB
 	if instance.Spec.DBType == v1beta1.SBDBType {
 		dbPortName = "south"
 		raftPortName = "south-raft"
-		dbPort = 6642
-		raftPort = 6644
+		dbPort = v1beta1.DBPortSB
+		raftPort = v1beta1.RaftPortSB
 	}
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
 			Namespace: instance.Namespace,
@@ -45,6 +56,35 @@ func Service(
 			},
 		},
 	}
+	// The RBAC restricted listener is used by the chassis only
+	if instance.Spec.EnableRBAC {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:     "south-chassis",
+			Port:     v1beta1.DBPortSBChassis,
+			Protocol: corev1.ProtocolTCP,
+		})
+	}
+	return svc
+}
+
+// LeaderServiceName - name of the Service selecting the RAFT leader
+func LeaderServiceName(serviceName string) string {
+	return serviceName + "-leader"
+}
+
+// LeaderService - Service selecting only the member labeled as RAFT leader
+func LeaderService(
+	serviceName string,
+	instance *ovnv1.OVNDBCluster,
+	serviceLabels map[string]string,
+	selector map[string]string,
+) *corev1.Service {
+	svc := Service(serviceName, instance, serviceLabels)
+	svc.Spec.Selector = map[string]string{RoleLabel: RoleLeader}
+	for key, value := range selector {
+		svc.Spec.Selector[key] = value
+	}
+	return svc
 }
 
 // HeadlessService - Headless Service for ovndbcluster pods to get DNS names in pods
@@ -54,10 +94,10 @@ func HeadlessService(
 	serviceLabels map[string]string,
 ) *corev1.Service {
 	raftPortName := "north-raft"
-	var raftPort int32 = 6643
-	if instance.Spec.DBType == "SB" {
+	var raftPort int32 = v1beta1.RaftPortNB
+	if instance.Spec.DBType == v1beta1.SBDBType {
 		raftPortName = "south-raft"
-		raftPort = 6644
+		raftPort = v1beta1.RaftPortSB
 	}
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -78,3 +118,13 @@ func HeadlessService(
 		},
 	}
 }
+
+// PodFQDN - DNS name of the member pod within the headless Service
+func PodFQDN(instance *ovnv1.OVNDBCluster, serviceName string, podName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc.%s", podName, serviceName, instance.Namespace, instance.GetClusterDomain())
+}
+
+// ServiceFQDN - DNS name of the per-pod Service of the member
+func ServiceFQDN(instance *ovnv1.OVNDBCluster, podName string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", podName, instance.Namespace, instance.GetClusterDomain())
+}
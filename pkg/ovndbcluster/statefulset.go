@@ -138,7 +138,7 @@ func StatefulSet(
 							Args:                     args,
 							Image:                    instance.Spec.ContainerImage,
 							Env:                      env.MergeEnvs([]corev1.EnvVar{}, envVars),
-							VolumeMounts:             GetDBClusterVolumeMounts(instance.Name + PvcSuffixEtcOvn),
+							VolumeMounts:             append(GetDBClusterVolumeMounts(instance.Name+PvcSuffixEtcOvn), GetTLSVolumeMounts(instance)...),
 							Resources:                instance.Spec.Resources,
 							ReadinessProbe:           readinessProbe,
 							LivenessProbe:            livenessProbe,
@@ -157,32 +157,46 @@ func StatefulSet(
 		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
 	}
 
-	blockOwnerDeletion := false
-	ownerRef := metav1.NewControllerRef(instance, instance.GroupVersionKind())
-	ownerRef.BlockOwnerDeletion = &blockOwnerDeletion
-
-	statefulset.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            instance.Name + PvcSuffixEtcOvn,
-				Namespace:       instance.Namespace,
-				Labels:          labels,
-				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+	statefulset.Spec.Template.Spec.Volumes = append(GetDBClusterVolumes(instance.Name), GetTLSVolumes(instance)...)
+	if instance.IsEphemeral() {
+		// the DB directory lives as long as the pod, no claim is needed
+		sizeLimit := resource.MustParse(instance.Spec.StorageRequest)
+		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: instance.Name + PvcSuffixEtcOvn,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    instance.Spec.StorageMedium,
+					SizeLimit: &sizeLimit,
+				},
 			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: []corev1.PersistentVolumeAccessMode{
-					corev1.ReadWriteOnce,
+		})
+	} else {
+		blockOwnerDeletion := false
+		ownerRef := metav1.NewControllerRef(instance, instance.GroupVersionKind())
+		ownerRef.BlockOwnerDeletion = &blockOwnerDeletion
+
+		statefulset.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            instance.Name + PvcSuffixEtcOvn,
+					Namespace:       instance.Namespace,
+					Labels:          labels,
+					OwnerReferences: []metav1.OwnerReference{*ownerRef},
 				},
-				StorageClassName: &instance.Spec.StorageClass,
-				Resources: corev1.ResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: resource.MustParse(instance.Spec.StorageRequest),
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{
+						corev1.ReadWriteOnce,
+					},
+					StorageClassName: &instance.Spec.StorageClass,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(instance.Spec.StorageRequest),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
-	statefulset.Spec.Template.Spec.Volumes = GetDBClusterVolumes(instance.Name)
 	// If possible two pods of the same service should not
 	// run on the same worker node. If this is not possible
 	// the get still created on the same worker node.
@@ -197,5 +211,12 @@ func StatefulSet(
 		statefulset.Spec.Template.Spec.NodeSelector = instance.Spec.NodeSelector
 	}
 
+	// The operator rolls out the RAFT members itself to restart the leader last
+	if !instance.IsActiveBackup() {
+		statefulset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.OnDeleteStatefulSetStrategyType,
+		}
+	}
+
 	return statefulset
 }
@@ -1,6 +1,20 @@
 package ovndbcluster
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TLSVolumeName - name of the volume holding the ovsdb-server certificates
+	TLSVolumeName = "ovsdb-tls"
+	// TLSCertPath - path of the ovsdb-server certificate
+	TLSCertPath = "/etc/pki/tls/certs/ovndb.crt"
+	// TLSKeyPath - path of the ovsdb-server private key
+	TLSKeyPath = "/etc/pki/tls/private/ovndb.key"
+	// TLSCACertPath - path of the CA certificate used to verify the peers
+	TLSCACertPath = "/etc/pki/tls/certs/ovndbca.crt"
+)
 
 // GetDBClusterVolumes -
 // TODO: merge to GetVolumes when other controllers also switched to current config
@@ -40,3 +54,50 @@ func GetDBClusterVolumeMounts(name string) []corev1.VolumeMount {
 	}
 
 }
+
+// GetTLSVolumes - ovsdb-server certificate volume, empty if TLS is disabled
+func GetTLSVolumes(instance *ovnv1.OVNDBCluster) []corev1.Volume {
+	if !instance.IsTLSEnabled() {
+		return []corev1.Volume{}
+	}
+	var tlsVolumeDefaultMode int32 = 0440
+
+	return []corev1.Volume{
+		{
+			Name: TLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  instance.Spec.TLS.SecretName,
+					DefaultMode: &tlsVolumeDefaultMode,
+				},
+			},
+		},
+	}
+}
+
+// GetTLSVolumeMounts - ovsdb-server certificate VolumeMounts, empty if TLS is disabled
+func GetTLSVolumeMounts(instance *ovnv1.OVNDBCluster) []corev1.VolumeMount {
+	if !instance.IsTLSEnabled() {
+		return []corev1.VolumeMount{}
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      TLSVolumeName,
+			MountPath: TLSCertPath,
+			SubPath:   "tls.crt",
+			ReadOnly:  true,
+		},
+		{
+			Name:      TLSVolumeName,
+			MountPath: TLSKeyPath,
+			SubPath:   "tls.key",
+			ReadOnly:  true,
+		},
+		{
+			Name:      TLSVolumeName,
+			MountPath: TLSCACertPath,
+			SubPath:   "ca.crt",
+			ReadOnly:  true,
+		},
+	}
+}
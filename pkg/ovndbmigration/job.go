@@ -0,0 +1,186 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovndbmigration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MigrateCommand -
+	MigrateCommand = "/usr/local/bin/container-scripts/migrate.sh"
+
+	// SourceTLSPath - mount path of the client certificate for the source database
+	SourceTLSPath = "/etc/pki/tls/source"
+	// TargetTLSPath - mount path of the client certificate for the target database
+	TargetTLSPath = "/etc/pki/tls/target"
+)
+
+// SyncJob - job copying the data of the source database into the target OVNDBCluster. Each copy of the
+// data is a separate job, identified by the number of copies finished before.
+func SyncJob(
+	instance *ovnv1.OVNDBMigration,
+	target *ovnv1.OVNDBCluster,
+	labels map[string]string,
+) *batchv1.Job {
+	backoffLimit := int32(2)
+	var scriptsVolumeDefaultMode int32 = 0755
+	var tlsVolumeDefaultMode int32 = 0440
+
+	image := instance.Spec.ContainerImage
+	if image == "" {
+		image = target.Spec.ContainerImage
+	}
+
+	envVars := map[string]env.Setter{}
+	// a new sync number re-runs the job
+	envVars["SYNC_NUMBER"] = env.SetValue(strconv.Itoa(int(instance.Status.Syncs)))
+	envVars["FINAL_SYNC"] = env.SetValue(strconv.FormatBool(instance.IsFinalSync()))
+
+	volumes := []corev1.Volume{
+		{
+			Name: "scripts",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					DefaultMode: &scriptsVolumeDefaultMode,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: instance.Name + "-scripts",
+					},
+				},
+			},
+		},
+		{
+			Name: "migration",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scripts",
+			MountPath: "/usr/local/bin/container-scripts",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "migration",
+			MountPath: "/var/lib/migration",
+		},
+	}
+	for _, secret := range []struct {
+		name      string
+		secret    string
+		mountPath string
+	}{
+		{"source-tls", instance.Spec.Source.TLSSecretName, SourceTLSPath},
+		{"target-tls", target.Spec.TLS.SecretName, TargetTLSPath},
+	} {
+		if secret.secret == "" {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: secret.name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  secret.secret,
+					DefaultMode: &tlsVolumeDefaultMode,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      secret.name,
+			MountPath: secret.mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(instance),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:         "migration",
+							Image:        image,
+							Command:      []string{"/bin/bash", "-c"},
+							Args:         []string{MigrateCommand},
+							Env:          env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts: volumeMounts,
+							// the result of the copy is reported in the termination message
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// JobName - name of the job running the next copy of the data
+func JobName(instance *ovnv1.OVNDBMigration) string {
+	return fmt.Sprintf("%s-sync-%d", instance.Name, instance.Status.Syncs)
+}
+
+// SyncResult - the result of a copy of the data reported by the job
+type SyncResult struct {
+	// Checksum - sha256 of the dump of the target database
+	Checksum string
+	// RowCounts - rows per table of the target database
+	RowCounts map[string]int64
+	// Final - true if the copy completed the migration
+	Final bool
+}
+
+// ParseSyncResult - parses the termination message of the job, made of key=value lines
+func ParseSyncResult(message string) (*SyncResult, error) {
+	result := &SyncResult{RowCounts: map[string]int64{}}
+	for _, line := range strings.Split(message, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		switch {
+		case key == "checksum":
+			result.Checksum = value
+		case key == "final":
+			result.Final = value == "true"
+		case strings.HasPrefix(key, "rows."):
+			rows, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid row count %s: %w", line, err)
+			}
+			result.RowCounts[strings.TrimPrefix(key, "rows.")] = rows
+		}
+	}
+	if result.Checksum == "" {
+		return nil, fmt.Errorf("no checksum reported")
+	}
+	return result, nil
+}
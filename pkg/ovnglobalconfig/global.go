@@ -0,0 +1,199 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnglobalconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovsdb"
+)
+
+const (
+	// NBDatabase - name of the NB database schema
+	NBDatabase = "OVN_Northbound"
+	// SBDatabase - name of the SB database schema
+	SBDatabase = "OVN_Southbound"
+
+	// NBGlobalTable - table of the global NB configuration
+	NBGlobalTable = "NB_Global"
+	// SBGlobalTable - table of the global SB configuration, ovn-northd copies the NB_Global options and
+	// ipsec flag into it
+	SBGlobalTable = "SB_Global"
+)
+
+// Global - the values of the NB_Global or SB_Global row managed by an OVNGlobalConfig
+type Global struct {
+	UUID    string
+	Options map[string]string
+	IPsec   bool
+
+	database string
+	table    string
+}
+
+// Table - returns NB_Global or SB_Global
+func (g *Global) Table() string {
+	return g.table
+}
+
+// Changes - the updates bringing the NB_Global or SB_Global row to the values of an OVNGlobalConfig
+type Changes struct {
+	// Set - options to add or to update
+	Set map[string]string
+	// Remove - managed options dropped from the spec
+	Remove []string
+	// IPsec - the new ipsec value, nil if it is unchanged
+	IPsec *bool
+}
+
+// IsEmpty - returns true if the row holds the values already
+func (c Changes) IsEmpty() bool {
+	return len(c.Set) == 0 && len(c.Remove) == 0 && c.IPsec == nil
+}
+
+// Describe - returns the changed columns, e.g. options:mac_prefix or ipsec
+func (c Changes) Describe() []string {
+	changed := []string{}
+	for key := range c.Set {
+		changed = append(changed, "options:"+key)
+	}
+	for _, key := range c.Remove {
+		changed = append(changed, "options:"+key)
+	}
+	sort.Strings(changed)
+	if c.IPsec != nil {
+		changed = append(changed, "ipsec")
+	}
+	return changed
+}
+
+// GetNBGlobal - reads the NB_Global row. It is created by ovn-northd.
+func GetNBGlobal(client *ovsdb.Client) (*Global, error) {
+	return getGlobal(client, NBDatabase, NBGlobalTable)
+}
+
+// GetSBGlobal - reads the SB_Global row. It is created by ovn-northd.
+func GetSBGlobal(client *ovsdb.Client) (*Global, error) {
+	return getGlobal(client, SBDatabase, SBGlobalTable)
+}
+
+func getGlobal(client *ovsdb.Client, database string, table string) (*Global, error) {
+	results, err := client.Transact(database, ovsdb.Operation{
+		Op:      "select",
+		Table:   table,
+		Where:   []interface{}{},
+		Columns: []string{"_uuid", "options", "ipsec"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results[0].Rows) != 1 {
+		return nil, fmt.Errorf("found %d %s rows instead of 1, it is created by ovn-northd",
+			len(results[0].Rows), table)
+	}
+	row := results[0].Rows[0]
+
+	global := &Global{database: database, table: table}
+	global.UUID, err = ovsdb.ParseUUID(row["_uuid"])
+	if err != nil {
+		return nil, err
+	}
+	global.Options, err = ovsdb.ParseMap(row["options"])
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(row["ipsec"], &global.IPsec)
+	if err != nil {
+		return nil, err
+	}
+	return global, nil
+}
+
+// GetChanges - compares the NB_Global or SB_Global row with the OVNGlobalConfig. The options it managed
+// before and which got dropped from the spec are removed.
+func GetChanges(instance *ovnv1.OVNGlobalConfig, global *Global) Changes {
+	changes := Changes{Set: map[string]string{}, Remove: []string{}}
+	for key, value := range instance.Spec.Options {
+		if current, ok := global.Options[key]; !ok || current != value {
+			changes.Set[key] = value
+		}
+	}
+	for _, key := range instance.Status.ManagedOptions {
+		if _, desired := instance.Spec.Options[key]; desired {
+			continue
+		}
+		if _, ok := global.Options[key]; ok {
+			changes.Remove = append(changes.Remove, key)
+		}
+	}
+	if instance.Spec.IPsec != nil && *instance.Spec.IPsec != global.IPsec {
+		ipsec := *instance.Spec.IPsec
+		changes.IPsec = &ipsec
+	}
+	return changes
+}
+
+// Apply - updates the NB_Global or SB_Global row in a single transaction
+func Apply(client *ovsdb.Client, global *Global, changes Changes) error {
+	where := []interface{}{[]interface{}{"_uuid", "==", ovsdb.UUID(global.UUID)}}
+	operations := []ovsdb.Operation{}
+
+	// a map insert keeps the existing keys, the updated ones are deleted first
+	deleted := append([]string{}, changes.Remove...)
+	for key := range changes.Set {
+		deleted = append(deleted, key)
+	}
+	sort.Strings(deleted)
+	mutations := []interface{}{}
+	if len(deleted) > 0 {
+		mutations = append(mutations, []interface{}{"options", "delete", ovsdb.Set(deleted)})
+	}
+	if len(changes.Set) > 0 {
+		mutations = append(mutations, []interface{}{"options", "insert", ovsdb.Map(changes.Set)})
+	}
+	if len(mutations) > 0 {
+		operations = append(operations, ovsdb.Operation{
+			Op:        "mutate",
+			Table:     global.table,
+			Where:     where,
+			Mutations: mutations,
+		})
+	}
+	if changes.IPsec != nil {
+		operations = append(operations, ovsdb.Operation{
+			Op:    "update",
+			Table: global.table,
+			Where: where,
+			Row:   map[string]interface{}{"ipsec": *changes.IPsec},
+		})
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+
+	_, err := client.Transact(global.database, operations...)
+	return err
+}
+
+// ManagedOptions - returns the sorted options of the OVNGlobalConfig
+func ManagedOptions(instance *ovnv1.OVNGlobalConfig) []string {
+	keys := []string{}
+	for key := range instance.Spec.Options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -37,6 +37,7 @@ func Deployment(
 	annotations map[string]string,
 	nbEndpoint string,
 	sbEndpoint string,
+	configHash string,
 ) *appsv1.Deployment {
 
 	livenessProbe := &corev1.Probe{
@@ -58,10 +59,28 @@ func Deployment(
 		fmt.Sprintf("--ovnnb-db=%s", nbEndpoint),
 		fmt.Sprintf("--ovnsb-db=%s", sbEndpoint),
 	}
+	if instance.IsTLSEnabled() {
+		args = append(args,
+			fmt.Sprintf("--private-key=%s", TLSKeyPath),
+			fmt.Sprintf("--certificate=%s", TLSCertPath),
+			fmt.Sprintf("--ca-cert=%s", TLSCACertPath),
+		)
+	}
+	args = append(args, TuningArgs(instance)...)
+
+	// an instance starting while paused is paused first
+	lifecycle := &corev1.Lifecycle{
+		PostStart: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/bash", "-c", PauseOnStartCommand()},
+			},
+		},
+	}
 
 	if instance.Spec.Debug.Service {
 		cmd = "/bin/sleep"
 		args = []string{"infinity"}
+		lifecycle = nil
 
 		noopCmd := []string{
 			"/bin/true",
@@ -77,17 +96,21 @@ func Deployment(
 		//
 		// https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/
 		//
+		// a disconnected instance is restarted after the connection timeout
 		livenessProbe.Exec = &corev1.ExecAction{
-			Command: []string{
-				"/usr/bin/pidof", "ovn-northd",
-			},
+			Command: LivenessCommand(),
+		}
+		livenessProbe.FailureThreshold = (instance.Spec.ConnectionTimeout + livenessProbe.PeriodSeconds - 1) /
+			livenessProbe.PeriodSeconds
+		readinessProbe.Exec = &corev1.ExecAction{
+			Command: ReadinessCommand(),
 		}
-		readinessProbe.Exec = livenessProbe.Exec
 	}
 
 	envVars := map[string]env.Setter{}
+	envVars["CONFIG_HASH"] = env.SetValue(configHash)
 	// TODO: Make confs customizable
-	envVars["OVN_RUNDIR"] = env.SetValue("/tmp")
+	envVars["OVN_RUNDIR"] = env.SetValue(RunDir)
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -117,13 +140,21 @@ func Deployment(
 							Resources:                instance.Spec.Resources,
 							ReadinessProbe:           readinessProbe,
 							LivenessProbe:            livenessProbe,
+							Lifecycle:                lifecycle,
+							VolumeMounts:             getVolumeMounts(instance),
 							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
+					Volumes: getVolumes(instance),
 				},
 			},
 		},
 	}
+	if instance.Spec.Metrics.Enabled {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers,
+			MetricsContainer(instance))
+	}
+
 	// If possible two pods of the same service should not
 	// run on the same worker node. If this is not possible
 	// the get still created on the same worker node.
@@ -140,3 +171,15 @@ func Deployment(
 
 	return deployment
 }
+
+func getVolumes(instance *ovnv1.OVNNorthd) []corev1.Volume {
+	volumes := GetTLSVolumes(instance)
+	volumes = append(volumes, GetMetricsVolumes(instance)...)
+	return append(volumes, GetPauseVolumes(instance)...)
+}
+
+func getVolumeMounts(instance *ovnv1.OVNNorthd) []corev1.VolumeMount {
+	mounts := GetTLSVolumeMounts(instance)
+	mounts = append(mounts, GetMetricsVolumeMounts(instance)...)
+	return append(mounts, GetPauseVolumeMounts()...)
+}
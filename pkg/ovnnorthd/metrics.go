@@ -0,0 +1,185 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnnorthd
+
+import (
+	"fmt"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// MetricsServiceName - name of the Service and ServiceMonitor of the metrics exporter
+	MetricsServiceName = "ovn-northd-metrics"
+	// MetricsContainerName - name of the metrics exporter sidecar
+	MetricsContainerName = "metrics-exporter"
+	// MetricsPortName - name of the metrics port
+	MetricsPortName = "metrics"
+	// RunDir - run directory of ovn-northd, shared with the metrics exporter for the control socket
+	RunDir = "/tmp"
+
+	scriptsVolumeName = "scripts"
+	scriptsPath       = "/usr/local/bin/container-scripts"
+	runDirVolumeName  = "rundir"
+)
+
+// ServiceMonitorGVK - the ServiceMonitor kind of the Prometheus operator
+var ServiceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+// ScriptsConfigMapName - name of the ConfigMap holding the metrics exporter
+func ScriptsConfigMapName(instance *ovnv1.OVNNorthd) string {
+	return fmt.Sprintf("%s-scripts", instance.Name)
+}
+
+// MetricsContainer - the sidecar publishing the engine statistics of the ovn-northd instance of the pod
+func MetricsContainer(instance *ovnv1.OVNNorthd) corev1.Container {
+	return corev1.Container{
+		Name:    MetricsContainerName,
+		Command: []string{"/usr/bin/python3"},
+		Args:    []string{scriptsPath + "/metrics_exporter.py"},
+		Image:   instance.GetMetricsContainerImage(),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          MetricsPortName,
+				ContainerPort: instance.Spec.Metrics.Port,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		SecurityContext: getOVNNorthdSecurityContext(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      scriptsVolumeName,
+				MountPath: scriptsPath,
+				ReadOnly:  true,
+			},
+			getRunDirVolumeMount(),
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// GetMetricsVolumes - volumes of the metrics exporter, empty if it is disabled
+func GetMetricsVolumes(instance *ovnv1.OVNNorthd) []corev1.Volume {
+	if !instance.Spec.Metrics.Enabled {
+		return []corev1.Volume{}
+	}
+	var scriptsVolumeDefaultMode int32 = 0755
+
+	return []corev1.Volume{
+		{
+			Name: scriptsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					DefaultMode: &scriptsVolumeDefaultMode,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: ScriptsConfigMapName(instance),
+					},
+				},
+			},
+		},
+		{
+			Name: runDirVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+}
+
+// GetMetricsVolumeMounts - VolumeMounts of the ovn-northd container sharing its control socket with the
+// metrics exporter, empty if it is disabled
+func GetMetricsVolumeMounts(instance *ovnv1.OVNNorthd) []corev1.VolumeMount {
+	if !instance.Spec.Metrics.Enabled {
+		return []corev1.VolumeMount{}
+	}
+	return []corev1.VolumeMount{getRunDirVolumeMount()}
+}
+
+func getRunDirVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      runDirVolumeName,
+		MountPath: RunDir,
+	}
+}
+
+// MetricsService - Service selecting the metrics exporters of the ovn-northd pods
+func MetricsService(
+	instance *ovnv1.OVNNorthd,
+	serviceLabels map[string]string,
+	selector map[string]string,
+) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MetricsServiceName,
+			Namespace: instance.Namespace,
+			Labels:    serviceLabels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       MetricsPortName,
+					Port:       instance.Spec.Metrics.Port,
+					TargetPort: intstr.FromString(MetricsPortName),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// ServiceMonitor - ServiceMonitor scraping the metrics Service. The Prometheus operator API is optional, the
+// object is unstructured.
+func ServiceMonitor(
+	instance *ovnv1.OVNNorthd,
+	serviceLabels map[string]string,
+) *unstructured.Unstructured {
+	labels := map[string]interface{}{}
+	selector := map[string]interface{}{}
+	for key, value := range serviceLabels {
+		labels[key] = value
+		selector[key] = value
+	}
+
+	monitor := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      MetricsServiceName,
+				"namespace": instance.Namespace,
+				"labels":    labels,
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": selector,
+				},
+				"endpoints": []interface{}{
+					map[string]interface{}{
+						"port": MetricsPortName,
+						"path": "/metrics",
+					},
+				},
+			},
+		},
+	}
+	monitor.SetGroupVersionKind(ServiceMonitorGVK)
+	return monitor
+}
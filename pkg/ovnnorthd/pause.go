@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnnorthd
+
+import (
+	"fmt"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PausedKey - key of the pause ConfigMap telling whether the OVNNorthd is paused
+	PausedKey = "paused"
+
+	pauseVolumeName = "pause"
+	pausePath       = "/etc/ovn-northd/pause"
+	// pauseRetries - seconds a starting instance waits for its control socket to be paused
+	pauseRetries = 30
+)
+
+// PauseConfigMapName - name of the ConfigMap the starting ovn-northd instances read the pause state from.
+// A ConfigMap volume is updated in place, changing it doesn't roll out the pods.
+func PauseConfigMapName(instance *ovnv1.OVNNorthd) string {
+	return fmt.Sprintf("%s-pause", instance.Name)
+}
+
+// PauseCommand - returns the command pausing the ovn-northd instance, it releases the SB lock
+func PauseCommand() []string {
+	return []string{"/bin/bash", "-c", ctlCommand + " pause"}
+}
+
+// ResumeCommand - returns the command resuming the ovn-northd instance
+func ResumeCommand() []string {
+	return []string{"/bin/bash", "-c", ctlCommand + " resume"}
+}
+
+// PauseOnStartCommand - returns the command pausing the ovn-northd instance starting as soon as its control
+// socket is up if the OVNNorthd is paused
+func PauseOnStartCommand() string {
+	return fmt.Sprintf(`if [[ "$(cat %s/%s 2>/dev/null)" == "true" ]]; then `+
+		`for i in $(seq %d); do %s pause && break; sleep 1; done; fi`,
+		pausePath, PausedKey, pauseRetries, ctlCommand)
+}
+
+// GetPauseVolumes - the volume of the pause ConfigMap
+func GetPauseVolumes(instance *ovnv1.OVNNorthd) []corev1.Volume {
+	optional := true
+	return []corev1.Volume{
+		{
+			Name: pauseVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: PauseConfigMapName(instance),
+					},
+					Optional: &optional,
+				},
+			},
+		},
+	}
+}
+
+// GetPauseVolumeMounts - the mount of the pause ConfigMap, without subPath to get its updates
+func GetPauseVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      pauseVolumeName,
+			MountPath: pausePath,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnnorthd
+
+import (
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RoleLabel - label the operator keeps on the ovn-northd pods with their role
+	RoleLabel = "ovn-role"
+	// RoleActive - role of the ovn-northd instance holding the SB lock
+	RoleActive = "active"
+	// RoleStandby - role of the other ovn-northd instances
+	RoleStandby = "standby"
+	// RoleUnknown - role of an ovn-northd instance which can't be queried
+	RoleUnknown = "unknown"
+	// RolePaused - role of a paused ovn-northd instance
+	RolePaused = "paused"
+)
+
+// ovn-northd runs without pidfile, its control socket is named after its pid
+const (
+	ctlCommand       = "ovn-appctl -t ${OVN_RUNDIR}/ovn-northd.$(pidof ovn-northd).ctl"
+	connectedCommand = `[[ "$(` + ctlCommand + ` nb-connection-status)" == "connected" ]] && ` +
+		`[[ "$(` + ctlCommand + ` sb-connection-status)" == "connected" ]]`
+)
+
+var statusRegexp = regexp.MustCompile(`(?m)^Status: (\S+)`)
+
+// StatusCommand - returns the command printing whether the ovn-northd instance is active, standby or paused
+func StatusCommand() []string {
+	return []string{"/bin/bash", "-c", ctlCommand + " status"}
+}
+
+// ReadinessCommand - returns the command succeeding if the ovn-northd instance is connected to the NB and SB
+// databases. An instance in standby or paused is ready as well, it takes over once it gets the SB lock.
+func ReadinessCommand() []string {
+	return []string{"/bin/bash", "-c", ctlCommand + ` status | grep -qE "^Status: (active|standby|paused)" && ` +
+		connectedCommand}
+}
+
+// LivenessCommand - returns the command succeeding if the ovn-northd instance is connected to the NB and SB
+// databases
+func LivenessCommand() []string {
+	return []string{"/bin/bash", "-c", connectedCommand}
+}
+
+// ParseStatus - parses the status output of an ovn-northd instance
+func ParseStatus(output string) string {
+	if m := statusRegexp.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// IsRunning - returns true if the ovn-northd container of the pod is running, ready or not. A pod which did
+// not start it yet reads the pause state when it does.
+func IsRunning(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == ServiceName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
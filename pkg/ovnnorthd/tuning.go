@@ -0,0 +1,37 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnnorthd
+
+import (
+	"fmt"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+)
+
+// TuningArgs - returns the ovn-northd arguments of the tuning settings
+func TuningArgs(instance *ovnv1.OVNNorthd) []string {
+	args := []string{}
+	if instance.Spec.Tuning.NThreads > 1 {
+		args = append(args, fmt.Sprintf("--n-threads=%d", instance.Spec.Tuning.NThreads))
+	}
+	return args
+}
+
+// TuningOptions - returns the NB_Global options of the tuning settings, read by ovn-northd from the database
+func TuningOptions(instance *ovnv1.OVNNorthd) map[string]string {
+	options := map[string]string{}
+	if instance.Spec.Tuning.ProbeInterval != nil {
+		options["northd_probe_interval"] = fmt.Sprintf("%d", *instance.Spec.Tuning.ProbeInterval)
+	}
+	return options
+}
@@ -0,0 +1,98 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovnnorthd
+
+import (
+	"fmt"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TLSVolumeName - name of the volume holding the ovn-northd client certificate
+	TLSVolumeName = "ovn-northd-tls"
+	// TLSCertPath - path of the ovn-northd client certificate
+	TLSCertPath = "/etc/pki/tls/certs/ovnnorthd.crt"
+	// TLSKeyPath - path of the ovn-northd private key
+	TLSKeyPath = "/etc/pki/tls/private/ovnnorthd.key"
+	// TLSCACertPath - path of the CA certificate verifying the ovsdb-servers
+	TLSCACertPath = "/etc/pki/tls/certs/ovnnorthdca.crt"
+	// CABundleVolumeName - name of the volume holding the CA bundle verifying the ovsdb-servers
+	CABundleVolumeName = "ovn-northd-ca-bundle"
+	// CABundleKey - key of the CA bundle in its ConfigMap
+	CABundleKey = "ca.crt"
+)
+
+// CABundleConfigMapName - name of the ConfigMap holding the CA of the client certificate and the CAs of the
+// external databases serving SSL
+func CABundleConfigMapName(instance *ovnv1.OVNNorthd) string {
+	return fmt.Sprintf("%s-ca-bundle", instance.Name)
+}
+
+// GetTLSVolumes - ovn-northd client certificate volume, empty if TLS is disabled
+func GetTLSVolumes(instance *ovnv1.OVNNorthd) []corev1.Volume {
+	if !instance.IsTLSEnabled() {
+		return []corev1.Volume{}
+	}
+	var tlsVolumeDefaultMode int32 = 0440
+
+	return []corev1.Volume{
+		{
+			Name: TLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  instance.Spec.TLS.SecretName,
+					DefaultMode: &tlsVolumeDefaultMode,
+				},
+			},
+		},
+		{
+			Name: CABundleVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: CABundleConfigMapName(instance),
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetTLSVolumeMounts - ovn-northd client certificate VolumeMounts, empty if TLS is disabled
+func GetTLSVolumeMounts(instance *ovnv1.OVNNorthd) []corev1.VolumeMount {
+	if !instance.IsTLSEnabled() {
+		return []corev1.VolumeMount{}
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      TLSVolumeName,
+			MountPath: TLSCertPath,
+			SubPath:   "tls.crt",
+			ReadOnly:  true,
+		},
+		{
+			Name:      TLSVolumeName,
+			MountPath: TLSKeyPath,
+			SubPath:   "tls.key",
+			ReadOnly:  true,
+		},
+		{
+			Name:      CABundleVolumeName,
+			MountPath: TLSCACertPath,
+			SubPath:   CABundleKey,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovntrace
+
+import (
+	"fmt"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TraceCommand -
+	TraceCommand = "/usr/local/bin/container-scripts/trace.sh"
+	// ContainerName - name of the container running ovn-trace, its log is the result of the trace
+	ContainerName = "trace"
+	// TLSPath - mount path of the client certificate for the SB database
+	TLSPath = "/etc/pki/tls/ovn"
+	// ResultKey - key of the output of ovn-trace in the result ConfigMap
+	ResultKey = "trace"
+	// MaxResultBytes - the output of ovn-trace stored, it has to fit in a ConfigMap
+	MaxResultBytes = 900 * 1024
+	// SBTimeout - seconds ovn-sbctl waits for the SB database
+	SBTimeout = 30
+	// JobDeadlineSeconds - the trace fails if the job runs longer, e.g. with an unreachable SB database
+	JobDeadlineSeconds = 300
+)
+
+// TraceJob - job running ovn-trace against the SB OVNDBCluster. A new generation of the spec is traced by a
+// separate job. The client certificate is mounted from the TLS Secret of the OVNTrace, a copy of the one of the
+// SB OVNDBCluster which may be in another namespace.
+func TraceJob(
+	instance *ovnv1.OVNTrace,
+	sbCluster *ovnv1.OVNDBCluster,
+	labels map[string]string,
+) *batchv1.Job {
+	// the trace is deterministic, retrying doesn't help
+	backoffLimit := int32(0)
+	activeDeadlineSeconds := int64(JobDeadlineSeconds)
+	var scriptsVolumeDefaultMode int32 = 0755
+	var tlsVolumeDefaultMode int32 = 0440
+
+	image := instance.Spec.ContainerImage
+	if image == "" {
+		image = sbCluster.Spec.ContainerImage
+	}
+
+	// the user input is passed in the environment to not be interpreted by the script
+	envVars := map[string]env.Setter{}
+	envVars["DATAPATH"] = env.SetValue(instance.Spec.Datapath)
+	envVars["MICROFLOW"] = env.SetValue(instance.Spec.Microflow)
+	envVars["SOURCE_PORT"] = env.SetValue(instance.Spec.SourcePort)
+	envVars["DESTINATION_IP"] = env.SetValue(instance.Spec.DestinationIP)
+	envVars["FORMAT"] = env.SetValue(instance.Spec.Format)
+
+	volumes := []corev1.Volume{
+		{
+			Name: "scripts",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					DefaultMode: &scriptsVolumeDefaultMode,
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: instance.Name + "-scripts",
+					},
+				},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scripts",
+			MountPath: "/usr/local/bin/container-scripts",
+			ReadOnly:  true,
+		},
+	}
+	if sbCluster.IsTLSEnabled() {
+		volumes = append(volumes, corev1.Volume{
+			Name: "sb-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  TLSSecretName(instance),
+					DefaultMode: &tlsVolumeDefaultMode,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "sb-tls",
+			MountPath: TLSPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(instance),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:         ContainerName,
+							Image:        image,
+							Command:      []string{"/bin/bash", "-c"},
+							Args:         []string{TraceCommand},
+							Env:          env.MergeEnvs([]corev1.EnvVar{}, envVars),
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// JobName - name of the job tracing the current generation of the spec
+func JobName(instance *ovnv1.OVNTrace) string {
+	return fmt.Sprintf("%s-trace-%d", instance.Name, instance.Generation)
+}
+
+// TLSSecretName - name of the Secret holding the copy of the SB client certificate, in the namespace of the
+// OVNTrace
+func TLSSecretName(instance *ovnv1.OVNTrace) string {
+	return fmt.Sprintf("%s-sb-tls", instance.Name)
+}
+
+// ResultConfigMapName - name of the ConfigMap holding the output of ovn-trace
+func ResultConfigMapName(instance *ovnv1.OVNTrace) string {
+	return fmt.Sprintf("%s-result", instance.Name)
+}
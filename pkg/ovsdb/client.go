@@ -0,0 +1,247 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovsdb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client - minimal OVSDB client (RFC 7047) running transactions on a single connection
+type Client struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+	timeout time.Duration
+	id      int
+}
+
+// Operation - operation of a transaction
+type Operation struct {
+	Op        string                 `json:"op"`
+	Table     string                 `json:"table"`
+	Where     []interface{}          `json:"where"`
+	Columns   []string               `json:"columns,omitempty"`
+	Row       map[string]interface{} `json:"row,omitempty"`
+	Mutations []interface{}          `json:"mutations,omitempty"`
+}
+
+// OperationResult - result of an operation of a transaction
+type OperationResult struct {
+	Rows    []map[string]json.RawMessage `json:"rows,omitempty"`
+	Count   int                          `json:"count,omitempty"`
+	Error   string                       `json:"error,omitempty"`
+	Details string                       `json:"details,omitempty"`
+}
+
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// Dial - connects to the first of the OVSDB remotes accepting the connection. The certificate of ssl
+// remotes is verified against the CA only, like the OVS clients do, as the remote may be an IP address.
+// The timeout applies to the connection and to each transaction.
+func Dial(ctx context.Context, remotes []string, tlsConfig *tls.Config, timeout time.Duration) (*Client, error) {
+	errs := []string{}
+	for _, remote := range remotes {
+		conn, err := dial(ctx, remote, tlsConfig, timeout)
+		if err == nil {
+			return &Client{
+				conn:    conn,
+				encoder: json.NewEncoder(conn),
+				decoder: json.NewDecoder(conn),
+				timeout: timeout,
+			}, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", remote, err.Error()))
+	}
+	return nil, fmt.Errorf("%s", strings.Join(errs, ", "))
+}
+
+func dial(ctx context.Context, remote string, tlsConfig *tls.Config, timeout time.Duration) (net.Conn, error) {
+	proto, address, found := strings.Cut(remote, ":")
+	if !found {
+		return nil, fmt.Errorf("invalid remote")
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	switch proto {
+	case "tcp":
+		return dialer.DialContext(ctx, "tcp", address)
+	case "ssl":
+		if tlsConfig == nil {
+			return nil, fmt.Errorf("no TLS configuration")
+		}
+		config := tlsConfig.Clone()
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(rawCerts, tlsConfig.RootCAs)
+		}
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: config}
+		return tlsDialer.DialContext(ctx, "tcp", address)
+	}
+	return nil, fmt.Errorf("unsupported protocol %s", proto)
+}
+
+func verifyChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no server certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// Close - closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Transact - runs the operations in a single transaction of the database and returns their results. A
+// failed operation fails the whole transaction.
+func (c *Client) Transact(db string, operations ...Operation) ([]OperationResult, error) {
+	err := c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	c.id++
+	params := []interface{}{db}
+	for _, operation := range operations {
+		params = append(params, operation)
+	}
+	err = c.encoder.Encode(map[string]interface{}{"method": "transact", "params": params, "id": c.id})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		msg := message{}
+		if err := c.decoder.Decode(&msg); err != nil {
+			return nil, err
+		}
+		// the server probes idle connections
+		if msg.Method == "echo" {
+			err = c.encoder.Encode(map[string]interface{}{"id": msg.ID, "result": msg.Params, "error": nil})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if string(msg.ID) != fmt.Sprint(c.id) {
+			continue
+		}
+		if len(msg.Error) > 0 && string(msg.Error) != "null" {
+			return nil, fmt.Errorf("transaction failed: %s", string(msg.Error))
+		}
+
+		results := []OperationResult{}
+		if err := json.Unmarshal(msg.Result, &results); err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			if result.Error != "" {
+				return nil, fmt.Errorf("transaction failed: %s: %s", result.Error, result.Details)
+			}
+		}
+		if len(results) < len(operations) {
+			return nil, fmt.Errorf("transaction failed: %d results for %d operations", len(results), len(operations))
+		}
+		return results, nil
+	}
+}
+
+// UUID - returns the OVSDB notation of the row UUID
+func UUID(uuid string) []interface{} {
+	return []interface{}{"uuid", uuid}
+}
+
+// Map - returns the OVSDB notation of the string map
+func Map(values map[string]string) []interface{} {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []interface{}{}
+	for _, key := range keys {
+		pairs = append(pairs, []interface{}{key, values[key]})
+	}
+	return []interface{}{"map", pairs}
+}
+
+// Set - returns the OVSDB notation of the string set
+func Set(values []string) []interface{} {
+	set := []interface{}{}
+	for _, value := range values {
+		set = append(set, value)
+	}
+	return []interface{}{"set", set}
+}
+
+// ParseUUID - parses the OVSDB notation of a row UUID
+func ParseUUID(raw json.RawMessage) (string, error) {
+	uuid := []string{}
+	if err := json.Unmarshal(raw, &uuid); err != nil || len(uuid) != 2 || uuid[0] != "uuid" {
+		return "", fmt.Errorf("invalid uuid %s", string(raw))
+	}
+	return uuid[1], nil
+}
+
+// ParseMap - parses the OVSDB notation of a string map
+func ParseMap(raw json.RawMessage) (map[string]string, error) {
+	notation := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &notation); err != nil || len(notation) != 2 || string(notation[0]) != `"map"` {
+		return nil, fmt.Errorf("invalid map %s", string(raw))
+	}
+	pairs := [][]string{}
+	if err := json.Unmarshal(notation[1], &pairs); err != nil {
+		return nil, fmt.Errorf("invalid map %s", string(raw))
+	}
+
+	values := map[string]string{}
+	for _, pair := range pairs {
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid map %s", string(raw))
+		}
+		values[pair[0]] = pair[1]
+	}
+	return values, nil
+}
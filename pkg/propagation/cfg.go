@@ -0,0 +1,159 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package propagation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	ovnv1 "github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/pkg/ovsdb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NBDatabase - name of the NB database schema
+	NBDatabase = "OVN_Northbound"
+	// SBDatabase - name of the SB database schema
+	SBDatabase = "OVN_Southbound"
+)
+
+// NBGlobalCfg - the sequence numbers of the NB_Global row
+type NBGlobalCfg struct {
+	// NBCfg - the requested NB configuration
+	NBCfg int64
+	// SBCfg - the NB configuration ovn-northd wrote to the SB database
+	SBCfg int64
+	// HVCfg - the NB configuration all the chassis applied
+	HVCfg int64
+}
+
+// ChassisCfg - the NB configuration applied by a chassis
+type ChassisCfg struct {
+	Name  string
+	NBCfg int64
+}
+
+// GetNBGlobalCfg - reads the sequence numbers of the NB_Global row
+func GetNBGlobalCfg(client *ovsdb.Client) (*NBGlobalCfg, error) {
+	row, err := selectGlobal(client, NBDatabase, "NB_Global", "nb_cfg", "sb_cfg", "hv_cfg")
+	if err != nil {
+		return nil, err
+	}
+	cfg := &NBGlobalCfg{}
+	for column, value := range map[string]*int64{"nb_cfg": &cfg.NBCfg, "sb_cfg": &cfg.SBCfg, "hv_cfg": &cfg.HVCfg} {
+		err = json.Unmarshal(row[column], value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NB_Global %s: %w", column, err)
+		}
+	}
+	return cfg, nil
+}
+
+// GetSBGlobalNBCfg - reads the NB configuration ovn-northd wrote to the SB_Global row, the one the chassis
+// catch up with
+func GetSBGlobalNBCfg(client *ovsdb.Client) (int64, error) {
+	row, err := selectGlobal(client, SBDatabase, "SB_Global", "nb_cfg")
+	if err != nil {
+		return 0, err
+	}
+	var nbCfg int64
+	err = json.Unmarshal(row["nb_cfg"], &nbCfg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid SB_Global nb_cfg: %w", err)
+	}
+	return nbCfg, nil
+}
+
+// GetChassisCfg - reads the NB configuration applied by each chassis, sorted by name
+func GetChassisCfg(client *ovsdb.Client) ([]ChassisCfg, error) {
+	results, err := client.Transact(SBDatabase, ovsdb.Operation{
+		Op:      "select",
+		Table:   "Chassis_Private",
+		Where:   []interface{}{},
+		Columns: []string{"name", "nb_cfg"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	chassis := []ChassisCfg{}
+	for _, row := range results[0].Rows {
+		cfg := ChassisCfg{}
+		err = json.Unmarshal(row["name"], &cfg.Name)
+		if err == nil {
+			err = json.Unmarshal(row["nb_cfg"], &cfg.NBCfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Chassis_Private row: %w", err)
+		}
+		chassis = append(chassis, cfg)
+	}
+	sort.Slice(chassis, func(i, j int) bool { return chassis[i].Name < chassis[j].Name })
+	return chassis, nil
+}
+
+func selectGlobal(client *ovsdb.Client, db string, table string, columns ...string) (map[string]json.RawMessage, error) {
+	results, err := client.Transact(db, ovsdb.Operation{
+		Op:      "select",
+		Table:   table,
+		Where:   []interface{}{},
+		Columns: columns,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results[0].Rows) != 1 {
+		return nil, fmt.Errorf("found %d %s rows instead of 1, it is created by ovn-northd",
+			len(results[0].Rows), table)
+	}
+	return results[0].Rows[0], nil
+}
+
+// Track - returns the lag of a reader of the NB configuration, nil if it reached the target. The lag keeps
+// going while the reader is behind the configuration it was already behind, it restarts when the reader
+// got past it but not up to a newer target. A reader which keeps making progress is never lagging for long.
+func Track(previous *ovnv1.OVNConfigLag, target int64, reached int64, now metav1.Time) *ovnv1.OVNConfigLag {
+	if reached >= target {
+		return nil
+	}
+	if previous != nil && reached < previous.NBCfg {
+		return previous.DeepCopy()
+	}
+	return &ovnv1.OVNConfigLag{NBCfg: target, Since: now}
+}
+
+// Exceeds - returns true if the lag lasts for longer than the threshold
+func Exceeds(lag *ovnv1.OVNConfigLag, threshold time.Duration, now metav1.Time) bool {
+	return lag != nil && now.Sub(lag.Since.Time) > threshold
+}
+
+// NextCheck - returns when to read the sequence numbers again, at the latest when a lag would exceed the
+// threshold
+func NextCheck(lags []*ovnv1.OVNConfigLag, threshold time.Duration, now metav1.Time) time.Duration {
+	next := time.Duration(60) * time.Second
+	for _, lag := range lags {
+		if lag == nil {
+			continue
+		}
+		remaining := lag.Since.Add(threshold).Sub(now.Time) + time.Second
+		if remaining <= 0 {
+			remaining = threshold
+		}
+		if remaining < next {
+			next = remaining
+		}
+	}
+	return next
+}
@@ -21,5 +21,6 @@ wait_for_ovsdb_server
 # From now on, we should exit immediatelly when any command exits with non-zero status
 set -ex
 
+configure_ssl
 configure_external_ids
 configure_physical_networks
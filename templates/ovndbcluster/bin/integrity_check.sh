@@ -0,0 +1,38 @@
+#!/usr/bin/env bash
+#
+# Copyright 2023 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -ex
+DB_TYPE="{{ .DB_TYPE }}"
+DB_FILE="/etc/ovn/ovn${DB_TYPE}_db.db"
+WORK_DIR="/var/lib/integrity-check"
+SCHEMA="/usr/share/ovn/ovn-${DB_TYPE}.ovsschema"
+
+# Work on a copy, the member keeps writing to its DB file
+cp ${DB_FILE} ${WORK_DIR}/ovn${DB_TYPE}_db.db
+{{- if eq .DB_MODE "active-backup" }}
+# Standalone DB, reading it back compacted validates every transaction
+ovsdb-tool compact ${WORK_DIR}/ovn${DB_TYPE}_db.db ${WORK_DIR}/standalone.db
+{{- else }}
+ovsdb-tool check-cluster ${WORK_DIR}/ovn${DB_TYPE}_db.db
+
+# The DB has to use the schema version shipped with the image
+ovsdb-tool cluster-to-standalone ${WORK_DIR}/standalone.db ${WORK_DIR}/ovn${DB_TYPE}_db.db
+{{- end }}
+DB_VERSION=$(ovsdb-tool db-version ${WORK_DIR}/standalone.db)
+SCHEMA_VERSION=$(ovsdb-tool schema-version ${SCHEMA})
+if [[ "${DB_VERSION}" != "${SCHEMA_VERSION}" ]]; then
+    echo "DB schema version ${DB_VERSION} does not match the image schema version ${SCHEMA_VERSION}"
+    exit 1
+fi
@@ -16,17 +16,46 @@
 set -ex
 DB_TYPE="{{ .DB_TYPE }}"
 DB_PORT="{{ .DB_PORT }}"
+PROTO="ptcp"
+{{- if .TLS }}
+PROTO="pssl"
+{{- end }}
+DB_TARGET="${PROTO}:${DB_PORT}:0.0.0.0"
+CONNECTIONS="${DB_TARGET}"
+{{- if .OVN_RBAC }}
+# ovn-controller clients use a dedicated listener restricted to the ovn-controller RBAC role
+CHASSIS_TARGET="${PROTO}:{{ .CHASSIS_DB_PORT }}:0.0.0.0"
+CONNECTIONS="${CONNECTIONS} ${CHASSIS_TARGET}"
+{{- end }}
 
 exec 1>/proc/1/fd/1 2>&1
 
-if [[ "$(hostname)" == "{{ .SERVICE_NAME }}-0" ]]; then
+configure_connections() {
     while [ ! -S /tmp/ovn${DB_TYPE}_db.ctl ]; do
         echo DB Server Not ready, waiting
         sleep 1
     done
 
-    while [ "$(ovn-${DB_TYPE}ctl --no-leader-only get connection . inactivity_probe)" != "{{ .OVN_INACTIVITY_PROBE }}" ]; do
-        ovn-${DB_TYPE}ctl --no-leader-only --inactivity-probe={{ .OVN_INACTIVITY_PROBE }} set-connection ptcp:${DB_PORT}:0.0.0.0
+    while [ "$(ovn-${DB_TYPE}ctl --no-leader-only get connection ${DB_TARGET} inactivity_probe)" != "{{ .OVN_INACTIVITY_PROBE }}" ]; do
+        ovn-${DB_TYPE}ctl --no-leader-only --inactivity-probe={{ .OVN_INACTIVITY_PROBE }} set-connection ${CONNECTIONS}
     done
+{{- if .OVN_RBAC }}
+    ovn-${DB_TYPE}ctl --no-leader-only set connection ${CHASSIS_TARGET} role=ovn-controller
+{{- end }}
     ovn-${DB_TYPE}ctl --no-leader-only list connection
+}
+
+{{- if eq .DB_MODE "active-backup" }}
+# Only the active member accepts writes, the backup replicates the connections from it.
+# Wait in the background for the operator to promote this member so the hook returns.
+(
+    while [ "$(ovs-appctl -t /tmp/ovn${DB_TYPE}_db.ctl ovsdb-server/sync-status 2>/dev/null | grep -c 'state: active')" != "1" ]; do
+        sleep 5
+    done
+    configure_connections
+) &
+{{- else }}
+if [[ "$(hostname)" == "{{ .SERVICE_NAME }}-0" ]]; then
+    configure_connections
 fi
+{{- end }}
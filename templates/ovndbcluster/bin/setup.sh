@@ -18,17 +18,64 @@ DB_TYPE="{{ .DB_TYPE }}"
 DB_PORT="{{ .DB_PORT }}"
 RAFT_PORT="{{ .RAFT_PORT }}"
 NAMESPACE="{{ .NAMESPACE }}"
+CLUSTER_DOMAIN="{{ .CLUSTER_DOMAIN }}"
 OPTS=""
+PROTO="tcp"
 DB_NAME="OVN_Northbound"
 if [[ "${DB_TYPE}" == "sb" ]]; then
     DB_NAME="OVN_Southbound"
 fi
+{{- if .TLS }}
+PROTO="ssl"
+OPTS="--ovn-${DB_TYPE}-db-ssl-key={{ .OVN_DB_KEY_PATH }} --ovn-${DB_TYPE}-db-ssl-cert={{ .OVN_DB_CERT_PATH }} --ovn-${DB_TYPE}-db-ssl-ca-cert={{ .OVN_DB_CA_CERT_PATH }}"
+{{- end }}
+{{- if eq .DB_MODE "active-backup" }}
+# Every member starts as a read-only backup replicating the other member, the operator
+# promotes one of them to active. A returning member thus never competes with the active one.
+PEER="{{ .SERVICE_NAME }}-0"
+if [[ "$(hostname)" == "{{ .SERVICE_NAME }}-0" ]]; then
+    PEER="{{ .SERVICE_NAME }}-1"
+fi
+/usr/local/bin/start-${DB_TYPE}-db-server --db-${DB_TYPE}-addr=0.0.0.0 --db-${DB_TYPE}-port=${DB_PORT} \
+--db-${DB_TYPE}-sync-from-proto=${PROTO} --db-${DB_TYPE}-sync-from-addr=${PEER}.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN} \
+--db-${DB_TYPE}-sync-from-port=${DB_PORT} --db-${DB_TYPE}-probe-interval-to-active={{ .OVN_PROBE_INTERVAL_TO_ACTIVE }} \
+--ovn-${DB_TYPE}-log=-vfile:{{ .OVN_LOG_LEVEL }} ${OPTS}
+{{- else }}
+{{- if .EPHEMERAL }}
+# The DB directory does not survive a restart, so a member always joins the cluster from scratch
+# through any serving member. Only the first member creates the cluster if none is serving it.
+rm -f /etc/ovn/ovn${DB_TYPE}_db.db
+JOIN_ADDR=""
+for ip in $(getent ahosts {{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN} | awk '{print $1}' | sort -u); do
+    if timeout 3 bash -c "</dev/tcp/${ip}/${RAFT_PORT}"; then
+        JOIN_ADDR=${ip}
+        break
+    fi
+done
+if [[ -z "${JOIN_ADDR}" && "$(hostname)" != "{{ .SERVICE_NAME }}-0" ]]; then
+    JOIN_ADDR={{ .SERVICE_NAME }}-0.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN}
+fi
+if [[ -n "${JOIN_ADDR}" ]]; then
+    OPTS="${OPTS} --db-${DB_TYPE}-cluster-remote-proto=${PROTO} --db-${DB_TYPE}-cluster-remote-addr=${JOIN_ADDR} --db-${DB_TYPE}-cluster-remote-port=${RAFT_PORT}"
+fi
+{{- else }}
 if [[ "$(hostname)" != "{{ .SERVICE_NAME }}-0" ]]; then
     rm -f /etc/ovn/ovn${DB_TYPE}_db.db
-    #ovsdb-tool join-cluster /etc/ovn/ovn${DB_TYPE}_db.db ${DB_NAME} tcp:$(hostname).{{ .SERVICE_NAME }}.${NAMESPACE}.svc.cluster.local:${RAFT_PORT} tcp:{{ .SERVICE_NAME }}-0.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.cluster.local:${RAFT_PORT}
-    OPTS="--db-${DB_TYPE}-cluster-remote-proto=tcp --db-${DB_TYPE}-cluster-remote-addr={{ .SERVICE_NAME }}-0.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.cluster.local --db-${DB_TYPE}-cluster-remote-port=${RAFT_PORT}"
+    #ovsdb-tool join-cluster /etc/ovn/ovn${DB_TYPE}_db.db ${DB_NAME} tcp:$(hostname).{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN}:${RAFT_PORT} tcp:{{ .SERVICE_NAME }}-0.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN}:${RAFT_PORT}
+    OPTS="${OPTS} --db-${DB_TYPE}-cluster-remote-proto=${PROTO} --db-${DB_TYPE}-cluster-remote-addr={{ .SERVICE_NAME }}-0.{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN} --db-${DB_TYPE}-cluster-remote-port=${RAFT_PORT}"
+elif [[ ! -e /etc/ovn/ovn${DB_TYPE}_db.db ]]; then
+    # A replaced first member joins the cluster through any serving member, it only creates the
+    # cluster if none is serving it
+    for ip in $(getent ahosts {{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN} | awk '{print $1}' | sort -u); do
+        if timeout 3 bash -c "</dev/tcp/${ip}/${RAFT_PORT}"; then
+            OPTS="${OPTS} --db-${DB_TYPE}-cluster-remote-proto=${PROTO} --db-${DB_TYPE}-cluster-remote-addr=${ip} --db-${DB_TYPE}-cluster-remote-port=${RAFT_PORT}"
+            break
+        fi
+    done
 fi
-/usr/local/bin/start-${DB_TYPE}-db-server --db-${DB_TYPE}-election-timer={{ .OVN_ELECTION_TIMER }} --db-${DB_TYPE}-cluster-local-proto=tcp \
---db-${DB_TYPE}-cluster-local-addr=$(hostname).{{ .SERVICE_NAME }}.${NAMESPACE}.svc.cluster.local --db-${DB_TYPE}-probe-interval-to-active={{ .OVN_PROBE_INTERVAL_TO_ACTIVE }} \
+{{- end }}
+/usr/local/bin/start-${DB_TYPE}-db-server --db-${DB_TYPE}-election-timer={{ .OVN_ELECTION_TIMER }} --db-${DB_TYPE}-cluster-local-proto=${PROTO} \
+--db-${DB_TYPE}-cluster-local-addr=$(hostname).{{ .SERVICE_NAME }}.${NAMESPACE}.svc.${CLUSTER_DOMAIN} --db-${DB_TYPE}-probe-interval-to-active={{ .OVN_PROBE_INTERVAL_TO_ACTIVE }} \
 --db-${DB_TYPE}-cluster-local-port=${RAFT_PORT} --db-${DB_TYPE}-addr=0.0.0.0 --db-${DB_TYPE}-port=${DB_PORT} \
 --ovn-${DB_TYPE}-log=-vfile:{{ .OVN_LOG_LEVEL }} ${OPTS}
+{{- end }}
@@ -0,0 +1,55 @@
+#!/usr/bin/env bash
+#
+# Copyright 2023 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+set -ex
+DB_NAME="{{ .DB_NAME }}"
+DB_TYPE="{{ .DB_TYPE }}"
+SOURCE="{{ .SOURCE }}"
+TARGET="{{ .TARGET }}"
+WORK_DIR="/var/lib/migration"
+SCHEMA="/usr/share/ovn/ovn-${DB_TYPE}.ovsschema"
+RESULT="/dev/termination-log"
+
+SOURCE_ARGS=""
+{{- if .SOURCE_TLS }}
+SOURCE_ARGS="-p {{ .SOURCE_TLS_PATH }}/tls.key -c {{ .SOURCE_TLS_PATH }}/tls.crt -C {{ .SOURCE_TLS_PATH }}/ca.crt"
+{{- end }}
+TARGET_ARGS=""
+{{- if .TARGET_TLS }}
+TARGET_ARGS="-p {{ .TARGET_TLS_PATH }}/tls.key -c {{ .TARGET_TLS_PATH }}/tls.crt -C {{ .TARGET_TLS_PATH }}/ca.crt"
+{{- end }}
+
+# Consistent snapshot of the source database
+ovsdb-client ${SOURCE_ARGS} backup ${SOURCE} ${DB_NAME} > ${WORK_DIR}/backup.db
+
+# The source may run an older OVN release, the target takes the schema shipped with the image
+DB_VERSION=$(ovsdb-tool db-version ${WORK_DIR}/backup.db)
+SCHEMA_VERSION=$(ovsdb-tool schema-version ${SCHEMA})
+if [[ "${DB_VERSION}" != "${SCHEMA_VERSION}" ]]; then
+    ovsdb-tool convert ${WORK_DIR}/backup.db ${SCHEMA} ${WORK_DIR}/converted.db
+    mv ${WORK_DIR}/converted.db ${WORK_DIR}/backup.db
+fi
+
+# Replaces the content of the target database, keeping the row UUIDs
+ovsdb-client ${TARGET_ARGS} restore ${TARGET} ${DB_NAME} < ${WORK_DIR}/backup.db
+
+# Report what the target holds now
+ovsdb-client ${TARGET_ARGS} dump -f csv ${TARGET} ${DB_NAME} > ${WORK_DIR}/dump.csv
+echo "checksum=$(sha256sum ${WORK_DIR}/dump.csv | cut -d ' ' -f 1)" > ${RESULT}
+echo "final=${FINAL_SYNC}" >> ${RESULT}
+awk '/ table$/ { table = $1; rows[table] = 0; getline; next }
+     /^$/ { table = ""; next }
+     table != "" { rows[table]++ }
+     END { for (table in rows) print "rows." table "=" rows[table] }' ${WORK_DIR}/dump.csv >> ${RESULT}
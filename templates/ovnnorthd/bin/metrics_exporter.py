@@ -0,0 +1,129 @@
+#!/usr/bin/env python3
+#
+# Copyright 2023 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# Publishes the engine statistics of the ovn-northd instance sharing the run
+# directory as Prometheus metrics. They are read through the control socket
+# on each scrape.
+
+import glob
+import http.server
+import re
+import subprocess
+
+PORT = {{ .METRICS_PORT }}
+RUNDIR = "{{ .OVN_RUNDIR }}"
+
+STOPWATCH_FIELDS = {
+    "Total samples": ("ovn_northd_stopwatch_samples_total", "counter",
+                      "Samples taken by the stopwatch"),
+    "Maximum": ("ovn_northd_stopwatch_maximum_milliseconds", "gauge",
+                "Longest duration measured by the stopwatch"),
+    "Minimum": ("ovn_northd_stopwatch_minimum_milliseconds", "gauge",
+                "Shortest duration measured by the stopwatch"),
+    "95th percentile": ("ovn_northd_stopwatch_p95_milliseconds", "gauge",
+                        "95th percentile of the durations measured by the stopwatch"),
+    "Short term average": ("ovn_northd_stopwatch_short_term_average_milliseconds", "gauge",
+                           "Short term average of the durations measured by the stopwatch"),
+    "Long term average": ("ovn_northd_stopwatch_long_term_average_milliseconds", "gauge",
+                          "Long term average of the durations measured by the stopwatch"),
+}
+
+ENGINE_FIELDS = {
+    "recompute": ("ovn_northd_inc_engine_recompute_total", "counter",
+                  "Full recomputes of the incremental processing engine node"),
+    "compute": ("ovn_northd_inc_engine_compute_total", "counter",
+                "Incremental computes of the incremental processing engine node"),
+    "cancel": ("ovn_northd_inc_engine_cancel_total", "counter",
+               "Canceled runs of the incremental processing engine node"),
+}
+
+STOPWATCH_RE = re.compile(r"^Statistics for '([^']+)'")
+STOPWATCH_FIELD_RE = re.compile(r"^\s+([^:]+):\s+([0-9.]+)")
+ENGINE_NODE_RE = re.compile(r"^Node: (\S+)", re.IGNORECASE)
+ENGINE_FIELD_RE = re.compile(r"^[\s-]*(recompute|compute|cancel)\s*:\s+([0-9]+)")
+
+
+def appctl(command):
+    sockets = glob.glob(RUNDIR + "/ovn-northd.*.ctl")
+    if not sockets:
+        raise RuntimeError("ovn-northd control socket not found")
+    return subprocess.run(["ovn-appctl", "-t", sockets[0], command],
+                          capture_output=True, text=True, timeout=10,
+                          check=True).stdout
+
+
+def parse(output, node_re, field_re, fields):
+    samples = {}
+    node = None
+    for line in output.splitlines():
+        match = node_re.match(line)
+        if match:
+            node = match.group(1)
+            continue
+        match = field_re.match(line)
+        if node and match and match.group(1) in fields:
+            samples.setdefault(match.group(1), []).append((node, match.group(2)))
+    return samples
+
+
+def render(samples, fields, label):
+    lines = []
+    for key, (name, kind, help_text) in fields.items():
+        if key not in samples:
+            continue
+        lines.append("# HELP %s %s" % (name, help_text))
+        lines.append("# TYPE %s %s" % (name, kind))
+        for node, value in samples[key]:
+            lines.append('%s{%s="%s"} %s' % (name, label, node, value))
+    return lines
+
+
+def collect():
+    lines = ["# HELP ovn_northd_up Whether the ovn-northd statistics could be read",
+             "# TYPE ovn_northd_up gauge"]
+    try:
+        stopwatch = appctl("stopwatch/show")
+        engine = appctl("inc-engine/show-stats")
+    except (RuntimeError, subprocess.SubprocessError) as e:
+        lines.append("ovn_northd_up 0")
+        print("Unable to read the ovn-northd statistics: %s" % e, flush=True)
+        return lines
+    lines.append("ovn_northd_up 1")
+    lines += render(parse(stopwatch, STOPWATCH_RE, STOPWATCH_FIELD_RE, STOPWATCH_FIELDS),
+                    STOPWATCH_FIELDS, "stopwatch")
+    lines += render(parse(engine, ENGINE_NODE_RE, ENGINE_FIELD_RE, ENGINE_FIELDS),
+                    ENGINE_FIELDS, "node")
+    return lines
+
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        if self.path != "/metrics":
+            self.send_error(404)
+            return
+        body = ("\n".join(collect()) + "\n").encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "text/plain; version=0.0.4")
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+    def log_message(self, format, *args):
+        pass
+
+
+if __name__ == "__main__":
+    http.server.ThreadingHTTPServer(("", PORT), Handler).serve_forever()
@@ -0,0 +1,90 @@
+#!/usr/bin/env bash
+#
+# Copyright 2023 Red Hat Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may
+# not use this file except in compliance with the License. You may obtain
+# a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations
+# under the License.
+
+# The output of the script is the result of the trace, no xtrace here
+set -e
+SB="{{ .SB }}"
+
+SB_ARGS=""
+{{- if .TLS }}
+SB_ARGS="--ca-cert={{ .TLS_PATH }}/ca.crt"
+{{- if .CLIENT_CERT }}
+SB_ARGS="${SB_ARGS} --private-key={{ .TLS_PATH }}/tls.key --certificate={{ .TLS_PATH }}/tls.crt"
+{{- end }}
+{{- end }}
+
+sbctl() {
+    ovn-sbctl --db=${SB} ${SB_ARGS} --timeout={{ .SB_TIMEOUT }} --bare "$@"
+}
+
+# The user input comes from the environment: DATAPATH, MICROFLOW, SOURCE_PORT, DESTINATION_IP and FORMAT
+if [[ -z "${MICROFLOW}" ]]; then
+    DATAPATH_UUID=$(sbctl --columns=datapath find Port_Binding logical_port="${SOURCE_PORT}")
+    if [[ -z "${DATAPATH_UUID}" ]]; then
+        echo "Logical port ${SOURCE_PORT} not found" >&2
+        exit 1
+    fi
+
+    IP="ip4"
+    if [[ "${DESTINATION_IP}" == *:* ]]; then
+        IP="ip6"
+    fi
+
+    # mac column: "MAC IP..." of the source port, the first address of the destination family is used
+    read -r SRC_MAC SRC_ADDRS <<< "$(sbctl --columns=mac find Port_Binding logical_port="${SOURCE_PORT}")"
+    SRC_IP=""
+    for addr in ${SRC_ADDRS}; do
+        addr=${addr%%/*}
+        if [[ "${IP}" == "ip6" && "${addr}" == *:* ]] || [[ "${IP}" == "ip4" && "${addr}" != *:* ]]; then
+            SRC_IP=${addr}
+            break
+        fi
+    done
+    if [[ -z "${SRC_MAC}" || -z "${SRC_IP}" ]]; then
+        echo "Logical port ${SOURCE_PORT} has no ${IP} address" >&2
+        exit 1
+    fi
+
+    # the port owning the destination on the same switch, or the router port of the switch
+    DST_MAC=""
+    while read -r mac addrs; do
+        for addr in ${addrs}; do
+            if [[ "${addr%%/*}" == "${DESTINATION_IP}" ]]; then
+                DST_MAC=${mac}
+            fi
+        done
+    done < <(sbctl --columns=mac find Port_Binding datapath=${DATAPATH_UUID})
+    if [[ -z "${DST_MAC}" ]]; then
+        for peer in $(sbctl --columns=options find Port_Binding datapath=${DATAPATH_UUID} type=patch | grep -o 'peer=[^ ]*' | cut -d= -f2); do
+            DST_MAC=$(sbctl --columns=mac find Port_Binding logical_port="${peer}" | awk '{ print $1; exit }')
+            if [[ -n "${DST_MAC}" ]]; then
+                break
+            fi
+        done
+    fi
+
+    MICROFLOW="inport == \"${SOURCE_PORT}\" && eth.src == ${SRC_MAC} && ${IP}.src == ${SRC_IP} && ${IP}.dst == ${DESTINATION_IP} && ip.ttl == 64"
+    if [[ -n "${DST_MAC}" ]]; then
+        MICROFLOW="${MICROFLOW} && eth.dst == ${DST_MAC}"
+    fi
+fi
+
+TRACE_ARGS=("--${FORMAT}")
+if [[ -n "${DATAPATH}" ]]; then
+    TRACE_ARGS+=("${DATAPATH}")
+fi
+echo "# microflow: ${MICROFLOW}"
+ovn-trace --db=${SB} ${SB_ARGS} "${TRACE_ARGS[@]}" "${MICROFLOW}"
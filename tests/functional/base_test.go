@@ -19,12 +19,16 @@ package functional_test
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -134,9 +138,18 @@ func CreateOVNDBClusters(namespace string, nad string) []types.NamespacedName {
 	return dbs
 }
 
-// DeleteOVNDBClusters Delete OVN DBClusters
+// DeleteOVNDBClusters Delete OVN DBClusters, forced as the dependent CRs of the outer test contexts
+// get cleaned up only afterwards
 func DeleteOVNDBClusters(names []types.NamespacedName) {
 	for _, db := range names {
+		Eventually(func(g Gomega) {
+			ovndbcluster := GetOVNDBCluster(db)
+			if ovndbcluster.Annotations == nil {
+				ovndbcluster.Annotations = map[string]string{}
+			}
+			ovndbcluster.Annotations[ovnv1.ForceDeleteAnnotation] = "true"
+			g.Expect(k8sClient.Update(ctx, ovndbcluster)).Should(Succeed())
+		}, timeout, interval).Should(Succeed())
 		th.DeleteInstance(GetOVNDBCluster(db))
 	}
 }
@@ -262,3 +275,312 @@ func SimulateDaemonsetNumberReadyWithPods(name types.NamespacedName, networkIPs
 
 	logger.Info("Simulated daemonset success", "on", name)
 }
+
+// CreateExternalOVNDBCluster Creates an external mode OVNDBCluster reachable at a local listener
+func CreateExternalOVNDBCluster(namespace string, dbType string) types.NamespacedName {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ShouldNot(HaveOccurred())
+	DeferCleanup(listener.Close)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	return CreateExternalOVNDBClusterAt(namespace, dbType, "tcp:"+listener.Addr().String())
+}
+
+// CreateExternalOVNDBClusterAt Creates an external mode OVNDBCluster with the given endpoint
+func CreateExternalOVNDBClusterAt(namespace string, dbType string, endpoint string) types.NamespacedName {
+	spec := GetDefaultOVNDBClusterSpec()
+	spec["dbType"] = dbType
+	spec["mode"] = v1beta1.ExternalMode
+	spec["external"] = map[string]interface{}{"endpoint": endpoint}
+	instance := CreateOVNDBCluster(namespace, fmt.Sprintf("ovn-%s", uuid.New().String()), spec)
+	DeferCleanup(th.DeleteInstance, instance)
+	return types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+}
+
+// FakeOVSDB - local OVSDB server serving a NB_Global or SB_Global row and the rows set by the test for the other
+// tables
+type FakeOVSDB struct {
+	Endpoint string
+
+	mu      sync.Mutex
+	options map[string]string
+	ipsec   bool
+	columns map[string]interface{}
+	tables  map[string][]map[string]interface{}
+}
+
+// StartFakeOVSDB - starts serving a NB_Global or SB_Global row with the options
+func StartFakeOVSDB(options map[string]string) *FakeOVSDB {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ShouldNot(HaveOccurred())
+	DeferCleanup(listener.Close)
+
+	fake := &FakeOVSDB{
+		Endpoint: "tcp:" + listener.Addr().String(),
+		options:  options,
+		columns:  map[string]interface{}{},
+		tables:   map[string][]map[string]interface{}{},
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+	return fake
+}
+
+func (fake *FakeOVSDB) serve(conn net.Conn) {
+	defer conn.Close()
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+	for {
+		request := struct {
+			ID     interface{}       `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}{}
+		if err := decoder.Decode(&request); err != nil {
+			return
+		}
+		results := []interface{}{}
+		for _, param := range request.Params[1:] {
+			op := struct {
+				Op        string                 `json:"op"`
+				Table     string                 `json:"table"`
+				Row       map[string]interface{} `json:"row"`
+				Mutations [][]interface{}        `json:"mutations"`
+			}{}
+			Expect(json.Unmarshal(param, &op)).Should(Succeed())
+			results = append(results, fake.run(op.Op, op.Table, op.Row, op.Mutations))
+		}
+		if err := encoder.Encode(map[string]interface{}{"id": request.ID, "result": results, "error": nil}); err != nil {
+			return
+		}
+	}
+}
+
+func (fake *FakeOVSDB) run(
+	op string,
+	table string,
+	row map[string]interface{},
+	mutations [][]interface{},
+) interface{} {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	switch op {
+	case "select":
+		if table != "NB_Global" && table != "SB_Global" {
+			return map[string]interface{}{"rows": fake.tables[table]}
+		}
+		pairs := []interface{}{}
+		for key, value := range fake.options {
+			pairs = append(pairs, []interface{}{key, value})
+		}
+		nbGlobal := map[string]interface{}{
+			"_uuid":   []interface{}{"uuid", "1c0e53d2-9bd9-4b8a-a3e5-1a7f3f7c9f21"},
+			"options": []interface{}{"map", pairs},
+			"ipsec":   fake.ipsec,
+		}
+		for column, value := range fake.columns {
+			nbGlobal[column] = value
+		}
+		return map[string]interface{}{"rows": []interface{}{nbGlobal}}
+	case "mutate":
+		for _, mutation := range mutations {
+			values := mutation[2].([]interface{})[1].([]interface{})
+			for _, value := range values {
+				if mutation[1] == "delete" {
+					delete(fake.options, value.(string))
+				} else {
+					pair := value.([]interface{})
+					fake.options[pair[0].(string)] = pair[1].(string)
+				}
+			}
+		}
+		return map[string]interface{}{"count": 1}
+	case "update":
+		fake.ipsec = row["ipsec"].(bool)
+		return map[string]interface{}{"count": 1}
+	}
+	return map[string]interface{}{"error": "not supported", "details": op}
+}
+
+// Options - returns a copy of the NB_Global or SB_Global options
+func (fake *FakeOVSDB) Options() map[string]string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	options := map[string]string{}
+	for key, value := range fake.options {
+		options[key] = value
+	}
+	return options
+}
+
+// IPsec - returns the NB_Global or SB_Global ipsec value
+func (fake *FakeOVSDB) IPsec() bool {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.ipsec
+}
+
+// SetOption - changes an NB_Global or SB_Global option like a manual ovn-nbctl or ovn-sbctl set would
+func (fake *FakeOVSDB) SetOption(key string, value string) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.options[key] = value
+}
+
+// SetNBGlobal - sets another column of the NB_Global row, e.g. nb_cfg
+func (fake *FakeOVSDB) SetNBGlobal(column string, value interface{}) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.columns[column] = value
+}
+
+// SetRows - replaces the rows of a table other than NB_Global and SB_Global
+func (fake *FakeOVSDB) SetRows(table string, rows ...map[string]interface{}) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.tables[table] = rows
+}
+
+// CreateOVNGlobalConfig -
+func CreateOVNGlobalConfig(namespace string, OVNGlobalConfigName string, spec map[string]interface{}) client.Object {
+	raw := map[string]interface{}{
+		"apiVersion": "ovn.openstack.org/v1beta1",
+		"kind":       "OVNGlobalConfig",
+		"metadata": map[string]interface{}{
+			"name":      OVNGlobalConfigName,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}
+	return th.CreateUnstructured(raw)
+}
+
+// GetOVNGlobalConfig -
+func GetOVNGlobalConfig(name types.NamespacedName) *ovnv1.OVNGlobalConfig {
+	instance := &ovnv1.OVNGlobalConfig{}
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, name, instance)).Should(Succeed())
+	}, timeout, interval).Should(Succeed())
+	return instance
+}
+
+// OVNGlobalConfigConditionGetter -
+func OVNGlobalConfigConditionGetter(name types.NamespacedName) condition.Conditions {
+	instance := GetOVNGlobalConfig(name)
+	return instance.Status.Conditions
+}
+
+// GetDefaultOVNDBMigrationSpec -
+func GetDefaultOVNDBMigrationSpec(targetCluster string) map[string]interface{} {
+	return map[string]interface{}{
+		"targetCluster": targetCluster,
+		"source": map[string]interface{}{
+			"endpoint": "tcp:192.0.2.1:6641",
+		},
+	}
+}
+
+// CreateOVNDBMigration -
+func CreateOVNDBMigration(namespace string, OVNDBMigrationName string, spec map[string]interface{}) client.Object {
+	raw := map[string]interface{}{
+		"apiVersion": "ovn.openstack.org/v1beta1",
+		"kind":       "OVNDBMigration",
+		"metadata": map[string]interface{}{
+			"name":      OVNDBMigrationName,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}
+	return th.CreateUnstructured(raw)
+}
+
+// GetOVNDBMigration -
+func GetOVNDBMigration(name types.NamespacedName) *ovnv1.OVNDBMigration {
+	instance := &ovnv1.OVNDBMigration{}
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, name, instance)).Should(Succeed())
+	}, timeout, interval).Should(Succeed())
+	return instance
+}
+
+// OVNDBMigrationConditionGetter -
+func OVNDBMigrationConditionGetter(name types.NamespacedName) condition.Conditions {
+	instance := GetOVNDBMigration(name)
+	return instance.Status.Conditions
+}
+
+// SimulateMigrationJobSuccess - creates the succeeded pod of the job reporting the result and marks the
+// job succeeded
+func SimulateMigrationJobSuccess(name types.NamespacedName, result string) {
+	job := th.GetJob(name)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name + "-pod",
+			Namespace: name.Namespace,
+			Labels:    map[string]string{"job-name": name.Name},
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+	pod.Spec.Volumes = []corev1.Volume{}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = []corev1.VolumeMount{}
+	}
+	Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, pod)).Should(Succeed())
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: pod.Spec.Containers[0].Name,
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: result},
+			},
+		}}
+		g.Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+	}, timeout, interval).Should(Succeed())
+
+	th.SimulateJobSuccess(name)
+}
+
+// CreateOVNTrace -
+func CreateOVNTrace(namespace string, OVNTraceName string, spec map[string]interface{}) client.Object {
+	raw := map[string]interface{}{
+		"apiVersion": "ovn.openstack.org/v1beta1",
+		"kind":       "OVNTrace",
+		"metadata": map[string]interface{}{
+			"name":      OVNTraceName,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}
+	return th.CreateUnstructured(raw)
+}
+
+// GetOVNTrace -
+func GetOVNTrace(name types.NamespacedName) *ovnv1.OVNTrace {
+	instance := &ovnv1.OVNTrace{}
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, name, instance)).Should(Succeed())
+	}, timeout, interval).Should(Succeed())
+	return instance
+}
+
+// OVNTraceConditionGetter -
+func OVNTraceConditionGetter(name types.NamespacedName) condition.Conditions {
+	instance := GetOVNTrace(name)
+	return instance.Status.Conditions
+}
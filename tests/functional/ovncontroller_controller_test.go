@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 )
 
 var _ = Describe("OVNController controller", func() {
@@ -915,4 +916,55 @@ var _ = Describe("OVNController controller", func() {
 			Expect(ovnController.Spec.ExternalIDS.SystemID).To(Equal("random"))
 		})
 	})
+
+	When("OVNController chassis catch up with the NB configuration", func() {
+		var OVNControllerName types.NamespacedName
+		var sbDB *FakeOVSDB
+		BeforeEach(func() {
+			sbDB = StartFakeOVSDB(map[string]string{})
+			sbDB.SetRows("SB_Global", map[string]interface{}{"nb_cfg": 7})
+			sbDB.SetRows("Chassis_Private",
+				map[string]interface{}{"name": "compute-0", "nb_cfg": 7},
+				map[string]interface{}{"name": "compute-1", "nb_cfg": 6},
+			)
+			sbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, sbDB.Endpoint)
+
+			name := fmt.Sprintf("ovn-controller-%s", uuid.New().String())
+			spec := GetDefaultOVNControllerSpec()
+			spec["sbClusterRef"] = map[string]interface{}{"name": sbCluster.Name}
+			spec["configLagThreshold"] = 1
+			instance := CreateOVNController(namespace, name, spec)
+			OVNControllerName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("reports the chassis which stopped catching up", func() {
+			Eventually(func(g Gomega) {
+				propagation := GetOVNController(OVNControllerName).Status.ConfigPropagation
+				g.Expect(propagation).NotTo(BeNil())
+				g.Expect(propagation.NBCfg).To(Equal(int64(7)))
+				g.Expect(propagation.Chassis).To(Equal(int32(2)))
+				g.Expect(propagation.LaggingChassis).To(HaveLen(1))
+				g.Expect(propagation.LaggingChassis[0].Name).To(Equal("compute-1"))
+				g.Expect(propagation.LaggingChassis[0].NBCfg).To(Equal(int64(6)))
+				g.Expect(propagation.LaggingChassis[0].Pending.NBCfg).To(Equal(int64(7)))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNControllerName,
+				ConditionGetterFunc(OVNControllerConditionGetter),
+				v1beta1.OVNConfigLaggingCondition,
+				corev1.ConditionTrue,
+			)
+
+			sbDB.SetRows("Chassis_Private",
+				map[string]interface{}{"name": "compute-0", "nb_cfg": 7},
+				map[string]interface{}{"name": "compute-1", "nb_cfg": 7},
+			)
+			Eventually(func(g Gomega) {
+				ovnController := GetOVNController(OVNControllerName)
+				g.Expect(ovnController.Status.ConfigPropagation.LaggingChassis).To(BeEmpty())
+				g.Expect(ovnController.Status.Conditions.Has(v1beta1.OVNConfigLaggingCondition)).Should(BeFalse())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
 })
@@ -19,6 +19,8 @@ package functional_test
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/google/uuid"
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
@@ -27,8 +29,17 @@ import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/ovn-operator/controllers"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var _ = Describe("OVNDBCluster controller", func() {
@@ -56,6 +67,13 @@ var _ = Describe("OVNDBCluster controller", func() {
 			Expect(OVNDBCluster.Status.ReadyCount).To(Equal(int32(0)))
 		})
 
+		It("should observe the spec generation", func() {
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.ObservedGeneration).To(Equal(OVNDBCluster.Generation))
+			}, timeout, interval).Should(Succeed())
+		})
+
 		It("should have a finalizer", func() {
 			// the reconciler loop adds the finalizer so we have to wait for
 			// it to run
@@ -91,6 +109,11 @@ var _ = Describe("OVNDBCluster controller", func() {
 			Entry("scripts CM", "scripts"),
 		)
 
+		It("rolls out the members itself", func() {
+			ss := th.GetStatefulSet(types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"})
+			Expect(ss.Spec.UpdateStrategy.Type).Should(Equal(appsv1.OnDeleteStatefulSetStrategyType))
+		})
+
 		It("should create a scripts ConfigMap with namespace from CR", func() {
 			cm := types.NamespacedName{
 				Namespace: namespace,
@@ -268,4 +291,693 @@ var _ = Describe("OVNDBCluster controller", func() {
 			)
 		})
 	})
+
+	When("A OVNDBCluster instance is created with the integrity check enabled", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["integrityCheck"] = map[string]interface{}{
+				"enabled": true,
+			}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("should have the default interval", func() {
+			OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+			Expect(OVNDBCluster.Spec.IntegrityCheck.Interval).Should(Equal(int32(1440)))
+		})
+
+		It("reports the integrity of the members once the cluster is up", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBIntegrityCondition,
+				corev1.ConditionTrue,
+			)
+		})
+	})
+
+	When("A RAFT OVNDBCluster instance is created with the integrity check enabled", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["replicas"] = 3
+			spec["integrityCheck"] = map[string]interface{}{
+				"enabled": true,
+			}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("keeps polling the RAFT state of the members until the next integrity check", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBIntegrityCondition,
+				corev1.ConditionTrue,
+			)
+
+			// the result of a reconcile is not visible through the API, the reconciler is run directly
+			kclient, err := kubernetes.NewForConfig(cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+			reconciler := &controllers.OVNDBClusterReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				Kclient:    kclient,
+				Recorder:   record.NewFakeRecorder(100),
+				RestConfig: cfg,
+			}
+			Eventually(func(g Gomega) {
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: OVNDBClusterName})
+				g.Expect(err).ShouldNot(HaveOccurred())
+				g.Expect(result.RequeueAfter).Should(BeNumerically(">", 0))
+				g.Expect(result.RequeueAfter).Should(BeNumerically("<=", time.Duration(60)*time.Second))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A SB OVNDBCluster instance is created with RBAC enabled", func() {
+		var OVNDBClusterName types.NamespacedName
+		var certSecretName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			certSecretName = types.NamespacedName{Namespace: namespace, Name: "ovndb-tls"}
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["dbType"] = v1beta1.SBDBType
+			spec["enableRBAC"] = true
+			spec["tls"] = map[string]interface{}{
+				"secretName": certSecretName.Name,
+			}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("reports that the TLS secret is missing", func() {
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.InputReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+
+		It("mounts the certificates and restricts the chassis listener to the ovn-controller role", func() {
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(certSecretName))
+
+			ss := th.GetStatefulSet(types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-sb"})
+			th.AssertVolumeExists("ovsdb-tls", ss.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("ovsdb-tls", "tls.crt", ss.Spec.Template.Spec.Containers[0].VolumeMounts)
+
+			cm := types.NamespacedName{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s", OVNDBClusterName.Name, "scripts"),
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(cm).Data["settings.sh"]).Should(
+					ContainSubstring("role=ovn-controller"))
+				g.Expect(th.GetConfigMap(cm).Data["setup.sh"]).Should(
+					ContainSubstring("PROTO=\"ssl\""))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A NB OVNDBCluster instance is created with RBAC enabled", func() {
+		It("is rejected by the webhook", func() {
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["enableRBAC"] = true
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNDBCluster",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovndbcluster-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("RBAC is only supported for the SB database"))
+			Expect(err.Error()).Should(ContainSubstring("RBAC requires TLS to identify the chassis"))
+		})
+	})
+
+	When("A OVNDBCluster instance is created in active-backup mode", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["mode"] = v1beta1.ActiveBackupMode
+			spec["replicas"] = 2
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("starts the members as backups syncing from each other", func() {
+			cm := types.NamespacedName{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s", OVNDBClusterName.Name, "scripts"),
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(cm).Data["setup.sh"]).Should(
+					ContainSubstring("--db-${DB_TYPE}-sync-from-addr=${PEER}"))
+				g.Expect(th.GetConfigMap(cm).Data["setup.sh"]).ShouldNot(
+					ContainSubstring("cluster-local-addr"))
+				g.Expect(th.GetConfigMap(cm).Data["settings.sh"]).Should(
+					ContainSubstring("ovsdb-server/sync-status"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("leaves the rollout to the StatefulSet", func() {
+			ss := th.GetStatefulSet(types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"})
+			Expect(ss.Spec.UpdateStrategy.Type).Should(Equal(appsv1.RollingUpdateStatefulSetStrategyType))
+		})
+
+		It("does not publish an address before a member got promoted", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			Consistently(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.ActiveMember).Should(BeEmpty())
+				g.Expect(OVNDBCluster.Status.InternalDBAddress).Should(BeEmpty())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejects changing the mode", func() {
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				OVNDBCluster.Spec.Mode = v1beta1.RaftMode
+				err := k8sClient.Update(ctx, OVNDBCluster)
+				g.Expect(err).Should(HaveOccurred())
+				g.Expect(err.Error()).Should(ContainSubstring("mode is immutable"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance is created in active-backup mode with 3 replicas", func() {
+		It("is rejected by the webhook", func() {
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["mode"] = v1beta1.ActiveBackupMode
+			spec["replicas"] = 3
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNDBCluster",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovndbcluster-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("active-backup mode supports at most 2 replicas"))
+		})
+	})
+
+	When("A OVNDBCluster instance is used by an OVNNorthd", func() {
+		var OVNDBClusterName types.NamespacedName
+		var northd client.Object
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			instance := CreateOVNDBCluster(namespace, name, GetDefaultOVNDBClusterSpec())
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			northd = CreateOVNNorthd(namespace, fmt.Sprintf("ovnnorthd-%s", uuid.New().String()), GetDefaultOVNNorthdSpec())
+			DeferCleanup(th.DeleteInstance, instance)
+			DeferCleanup(th.DeleteInstance, northd)
+		})
+
+		It("rejects the deletion", func() {
+			err := k8sClient.Delete(ctx, GetOVNDBCluster(OVNDBClusterName))
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("still used by OVNNorthd/" + northd.GetName()))
+		})
+
+		It("rejects serving SSL while the OVNNorthd has no client certificate", func() {
+			OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+			OVNDBCluster.Spec.TLS.SecretName = "ovndb-tls"
+			err := k8sClient.Update(ctx, OVNDBCluster)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("OVNNorthd/" + northd.GetName() + " have no client certificate"))
+		})
+
+		It("is deleted once forced", func() {
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				OVNDBCluster.Annotations = map[string]string{v1beta1.ForceDeleteAnnotation: "true"}
+				g.Expect(k8sClient.Update(ctx, OVNDBCluster)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			th.DeleteInstance(GetOVNDBCluster(OVNDBClusterName))
+		})
+	})
+
+	When("A OVNDBCluster instance is created with ephemeral storage", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["storageMode"] = v1beta1.EphemeralStorage
+			spec["storageMedium"] = string(corev1.StorageMediumMemory)
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("stores the DB files on an emptyDir", func() {
+			ss := th.GetStatefulSet(types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"})
+			Expect(ss.Spec.VolumeClaimTemplates).Should(BeEmpty())
+			th.AssertVolumeExists(OVNDBClusterName.Name+"-etc-ovn", ss.Spec.Template.Spec.Volumes)
+			for _, volume := range ss.Spec.Template.Spec.Volumes {
+				if volume.Name == OVNDBClusterName.Name+"-etc-ovn" {
+					Expect(volume.EmptyDir).ShouldNot(BeNil())
+					Expect(volume.EmptyDir.Medium).Should(Equal(corev1.StorageMediumMemory))
+				}
+			}
+		})
+
+		It("reports the data as non-persistent", func() {
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.EphemeralStorage).Should(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejoins the cluster from scratch on restart", func() {
+			cm := types.NamespacedName{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s", OVNDBClusterName.Name, "scripts"),
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(cm).Data["setup.sh"]).Should(
+					ContainSubstring("--db-${DB_TYPE}-cluster-remote-addr=${JOIN_ADDR}"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance with 3 replicas has a single member ready", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["replicas"] = 3
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("reports Degraded instead of Ready", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBClusterDegradedCondition,
+				corev1.ConditionTrue,
+			)
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.DeploymentReadyCondition,
+				corev1.ConditionFalse,
+			)
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+
+		It("is not Degraded while all the members are ready but not joined yet", func() {
+			statefulSetName := types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"}
+			th.SimulateStatefulSetReplicaReadyWithPods(statefulSetName, map[string][]string{})
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBClusterDegradedCondition,
+				corev1.ConditionTrue,
+			)
+
+			Eventually(func(g Gomega) {
+				ss := th.GetStatefulSet(statefulSetName)
+				ss.Status.Replicas = 3
+				ss.Status.ReadyReplicas = 3
+				g.Expect(k8sClient.Status().Update(ctx, ss)).To(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				instance := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(instance.Status.ReadyCount).To(Equal(int32(3)))
+				g.Expect(instance.Status.Conditions.Has(v1beta1.OVNDBClusterDegradedCondition)).To(BeFalse())
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.DeploymentReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+	})
+
+	When("A OVNDBCluster instance is created with the PodDNS address mode", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["addressMode"] = v1beta1.PodDNSAddressMode
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("defaults the cluster domain", func() {
+			OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+			Expect(OVNDBCluster.Spec.ClusterDomain).Should(Equal(v1beta1.DefaultClusterDomain))
+
+			cm := types.NamespacedName{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s", OVNDBClusterName.Name, "scripts"),
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(cm).Data["setup.sh"]).Should(
+					ContainSubstring("CLUSTER_DOMAIN=\"cluster.local\""))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("publishes the DNS names of the pods", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.InternalDBAddress).Should(Equal(
+					fmt.Sprintf("tcp:ovsdbserver-nb-0.ovsdbserver-nb.%s.svc.cluster.local:6641", namespace)))
+				g.Expect(OVNDBCluster.Status.RaftAddress).Should(Equal(
+					fmt.Sprintf("tcp:ovsdbserver-nb-0.ovsdbserver-nb.%s.svc.cluster.local:6643", namespace)))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance is created with the leader Service", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["leaderService"] = true
+			spec["addressMode"] = v1beta1.ServiceDNSAddressMode
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("selects the member labeled as leader and publishes its address", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			Eventually(func(g Gomega) {
+				svc := &corev1.Service{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb-leader"}, svc)).Should(Succeed())
+				g.Expect(svc.Spec.Selector).Should(HaveKeyWithValue("ovn-role", "leader"))
+				g.Expect(svc.Spec.Selector).Should(HaveKeyWithValue("service", "ovsdbserver-nb"))
+
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.InternalLeaderDBAddress).Should(Equal(
+					fmt.Sprintf("tcp:ovsdbserver-nb-leader.%s.svc.cluster.local:6641", namespace)))
+				// the leader Service is not a member
+				g.Expect(OVNDBCluster.Status.InternalDBAddress).Should(Equal(
+					fmt.Sprintf("tcp:ovsdbserver-nb-0.%s.svc.cluster.local:6641", namespace)))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("deletes the leader Service once disabled", func() {
+			leaderSvc := types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb-leader"}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, leaderSvc, &corev1.Service{})).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				OVNDBCluster.Spec.LeaderService = false
+				g.Expect(k8sClient.Update(ctx, OVNDBCluster)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, leaderSvc, &corev1.Service{})
+				g.Expect(k8s_errors.IsNotFound(err)).Should(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance is created with members to replace", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["replicas"] = 3
+			spec["replaceMembers"] = []string{"ovsdbserver-nb-2"}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("waits for a leader before replacing the member", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			Consistently(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.Replacements).Should(BeEmpty())
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb-2"},
+					&corev1.Pod{})).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("rejects members which are not part of the cluster", func() {
+			Eventually(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				OVNDBCluster.Spec.ReplaceMembers = []string{"ovsdbserver-nb-3"}
+				err := k8sClient.Update(ctx, OVNDBCluster)
+				g.Expect(err).Should(HaveOccurred())
+				g.Expect(err.Error()).Should(ContainSubstring("spec.replaceMembers[0]"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance with fewer than 3 replicas is created with members to replace", func() {
+		It("is rejected by the webhook", func() {
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["replicas"] = 2
+			spec["replaceMembers"] = []string{"ovsdbserver-nb-1"}
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNDBCluster",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovndbcluster-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("replacing members requires at least 3 replicas"))
+		})
+	})
+
+	When("A OVNDBCluster instance is created with automatic healing", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["replicas"] = 3
+			spec["autoHeal"] = map[string]interface{}{"enabled": true}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("has the default rate limits", func() {
+			OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+			Expect(OVNDBCluster.Spec.AutoHeal.RestartThreshold).Should(Equal(int32(5)))
+			Expect(OVNDBCluster.Spec.AutoHeal.MinInterval).Should(Equal(int32(60)))
+		})
+
+		It("does not rebuild members which are not crashlooping", func() {
+			th.SimulateStatefulSetReplicaReadyWithPods(
+				types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"},
+				map[string][]string{},
+			)
+
+			Consistently(func(g Gomega) {
+				OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+				g.Expect(OVNDBCluster.Status.AutoHeals).Should(BeEmpty())
+				g.Expect(OVNDBCluster.Status.Replacements).Should(BeEmpty())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNDBCluster instance with ephemeral storage is created with automatic healing", func() {
+		It("is rejected by the webhook", func() {
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["storageMode"] = v1beta1.EphemeralStorage
+			spec["autoHeal"] = map[string]interface{}{"enabled": true}
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNDBCluster",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovndbcluster-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("automatic healing requires persistent storage"))
+		})
+	})
+
+	When("A OVNDBCluster instance is created in external mode", func() {
+		var OVNDBClusterName types.NamespacedName
+		var endpoint string
+		BeforeEach(func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ShouldNot(HaveOccurred())
+			DeferCleanup(listener.Close)
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					conn.Close()
+				}
+			}()
+			endpoint = "tcp:" + listener.Addr().String()
+
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["mode"] = v1beta1.ExternalMode
+			spec["external"] = map[string]interface{}{"endpoint": endpoint}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("publishes the external endpoint and is ready once reachable", func() {
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBReachableCondition,
+				corev1.ConditionTrue,
+			)
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionTrue,
+			)
+			OVNDBCluster := GetOVNDBCluster(OVNDBClusterName)
+			Expect(OVNDBCluster.Status.InternalDBAddress).To(Equal(endpoint))
+			Expect(OVNDBCluster.Status.DBAddress).To(Equal(endpoint))
+		})
+
+		It("does not deploy a StatefulSet", func() {
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionTrue,
+			)
+			th.AssertStatefulSetDoesNotExist(types.NamespacedName{Namespace: namespace, Name: "ovsdbserver-nb"})
+		})
+	})
+
+	When("A OVNDBCluster instance in external mode can't reach the database", func() {
+		var OVNDBClusterName types.NamespacedName
+		BeforeEach(func() {
+			// grab a free port nothing listens on
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ShouldNot(HaveOccurred())
+			endpoint := "tcp:" + listener.Addr().String()
+			Expect(listener.Close()).Should(Succeed())
+
+			name := fmt.Sprintf("ovndbcluster-%s", uuid.New().String())
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["mode"] = v1beta1.ExternalMode
+			spec["external"] = map[string]interface{}{"endpoint": endpoint}
+			instance := CreateOVNDBCluster(namespace, name, spec)
+			OVNDBClusterName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("is not ready", func() {
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				v1beta1.OVNDBReachableCondition,
+				corev1.ConditionFalse,
+			)
+			th.ExpectCondition(
+				OVNDBClusterName,
+				ConditionGetterFunc(OVNDBClusterConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+	})
+
+	When("A OVNDBCluster instance is created in external mode without endpoint", func() {
+		It("is rejected", func() {
+			spec := GetDefaultOVNDBClusterSpec()
+			spec["mode"] = v1beta1.ExternalMode
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNDBCluster",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovndbcluster-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("external mode requires the endpoint of the database"))
+		})
+	})
 })
@@ -0,0 +1,166 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package functional_test
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const migrationResult = `checksum=0123abcd
+final=%t
+rows.Logical_Switch=2
+rows.Logical_Router=1
+`
+
+var _ = Describe("OVNDBMigration controller", func() {
+
+	When("A OVNDBMigration instance is created", func() {
+		var OVNDBMigrationName types.NamespacedName
+		var target types.NamespacedName
+		BeforeEach(func() {
+			target = CreateExternalOVNDBCluster(namespace, v1beta1.NBDBType)
+			name := fmt.Sprintf("ovndbmigration-%s", uuid.New().String())
+			instance := CreateOVNDBMigration(namespace, name, GetDefaultOVNDBMigrationSpec(target.Name))
+			OVNDBMigrationName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("runs a job copying the data into the target", func() {
+			job := th.GetJob(types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-0"})
+			Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("test-ovn-nb-container-image"))
+
+			scripts := th.GetConfigMap(types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-scripts"})
+			Expect(scripts.Data["migrate.sh"]).To(ContainSubstring(`SOURCE="tcp:192.0.2.1:6641"`))
+			Expect(scripts.Data["migrate.sh"]).To(ContainSubstring(`DB_NAME="OVN_Northbound"`))
+			Expect(scripts.Data["migrate.sh"]).To(ContainSubstring(
+				fmt.Sprintf(`TARGET="%s"`, GetOVNDBCluster(target).Status.InternalDBAddress)))
+		})
+
+		It("reports the result and completes once the job succeeded", func() {
+			SimulateMigrationJobSuccess(
+				types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-0"},
+				fmt.Sprintf(migrationResult, true))
+
+			th.ExpectCondition(
+				OVNDBMigrationName,
+				ConditionGetterFunc(OVNDBMigrationConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionTrue,
+			)
+			migration := GetOVNDBMigration(OVNDBMigrationName)
+			Expect(migration.Status.Phase).To(Equal(v1beta1.MigrationPhaseCompleted))
+			Expect(migration.Status.Syncs).To(Equal(int32(1)))
+			Expect(migration.Status.Checksum).To(Equal("0123abcd"))
+			Expect(migration.Status.RowCounts).To(Equal(map[string]int64{
+				"Logical_Switch": 2,
+				"Logical_Router": 1,
+			}))
+		})
+
+		It("records an Event for the finished copy", func() {
+			SimulateMigrationJobSuccess(
+				types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-0"},
+				fmt.Sprintf(migrationResult, true))
+
+			Eventually(func(g Gomega) {
+				events := &corev1.EventList{}
+				g.Expect(k8sClient.List(ctx, events, client.InNamespace(namespace))).Should(Succeed())
+				reasons := []string{}
+				for _, event := range events.Items {
+					if event.InvolvedObject.Name == OVNDBMigrationName.Name {
+						reasons = append(reasons, event.Reason)
+					}
+				}
+				g.Expect(reasons).To(ContainElement("SyncSucceeded"))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("fails if the job fails", func() {
+			th.SimulateJobFailure(types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-0"})
+
+			th.ExpectCondition(
+				OVNDBMigrationName,
+				ConditionGetterFunc(OVNDBMigrationConditionGetter),
+				v1beta1.OVNDBMigrationCompletedCondition,
+				corev1.ConditionFalse,
+			)
+			Expect(GetOVNDBMigration(OVNDBMigrationName).Status.Phase).To(Equal(v1beta1.MigrationPhaseFailed))
+		})
+	})
+
+	When("A OVNDBMigration instance is created with continuous sync", func() {
+		var OVNDBMigrationName types.NamespacedName
+		BeforeEach(func() {
+			target := CreateExternalOVNDBCluster(namespace, v1beta1.NBDBType)
+			name := fmt.Sprintf("ovndbmigration-%s", uuid.New().String())
+			spec := GetDefaultOVNDBMigrationSpec(target.Name)
+			spec["continuousSync"] = true
+			instance := CreateOVNDBMigration(namespace, name, spec)
+			OVNDBMigrationName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+
+			SimulateMigrationJobSuccess(
+				types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-0"},
+				fmt.Sprintf(migrationResult, false))
+		})
+
+		It("keeps syncing until cutover", func() {
+			Eventually(func(g Gomega) {
+				migration := GetOVNDBMigration(OVNDBMigrationName)
+				g.Expect(migration.Status.Phase).To(Equal(v1beta1.MigrationPhaseSyncing))
+				g.Expect(migration.Status.Syncs).To(Equal(int32(1)))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNDBMigrationName,
+				ConditionGetterFunc(OVNDBMigrationConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+
+		It("completes with a final sync on cutover", func() {
+			Eventually(func(g Gomega) {
+				migration := GetOVNDBMigration(OVNDBMigrationName)
+				g.Expect(migration.Status.Syncs).To(Equal(int32(1)))
+				migration.Spec.Cutover = true
+				g.Expect(k8sClient.Update(ctx, migration)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			SimulateMigrationJobSuccess(
+				types.NamespacedName{Namespace: namespace, Name: OVNDBMigrationName.Name + "-sync-1"},
+				fmt.Sprintf(migrationResult, true))
+
+			th.ExpectCondition(
+				OVNDBMigrationName,
+				ConditionGetterFunc(OVNDBMigrationConditionGetter),
+				condition.ReadyCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(GetOVNDBMigration(OVNDBMigrationName).Status.Phase).To(Equal(v1beta1.MigrationPhaseCompleted))
+		})
+	})
+})
@@ -0,0 +1,198 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package functional_test
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+)
+
+var _ = Describe("OVNGlobalConfig controller", func() {
+
+	When("A OVNGlobalConfig instance is created without the NB and SB OVNDBClusters", func() {
+		var OVNGlobalConfigName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovnglobalconfig-%s", uuid.New().String())
+			instance := CreateOVNGlobalConfig(namespace, name, map[string]interface{}{})
+			OVNGlobalConfigName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("should have the Spec fields initialized", func() {
+			Expect(GetOVNGlobalConfig(OVNGlobalConfigName).Spec.ResyncInterval).Should(Equal(int32(60)))
+		})
+
+		It("waits for the OVNDBClusters", func() {
+			th.ExpectCondition(
+				OVNGlobalConfigName,
+				ConditionGetterFunc(OVNGlobalConfigConditionGetter),
+				condition.InputReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+	})
+
+	When("A OVNGlobalConfig instance sets the probe interval of ovn-northd", func() {
+		It("is accepted", func() {
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNGlobalConfig",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovnglobalconfig-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"options": map[string]interface{}{"northd_probe_interval": "10000"},
+				},
+			}
+			instance := &unstructured.Unstructured{Object: raw}
+			Expect(k8sClient.Create(ctx, instance)).Should(Succeed())
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+	})
+
+	When("A OVNGlobalConfig instance is created for the NB and SB databases", func() {
+		var OVNGlobalConfigName types.NamespacedName
+		var nbGlobal *FakeOVSDB
+		var sbGlobal *FakeOVSDB
+		BeforeEach(func() {
+			nbGlobal = StartFakeOVSDB(map[string]string{
+				"mac_prefix":      "0a:00:00",
+				"e2e_test_option": "kept",
+			})
+			CreateExternalOVNDBClusterAt(namespace, v1beta1.NBDBType, nbGlobal.Endpoint)
+			sbGlobal = StartFakeOVSDB(map[string]string{
+				"mac_prefix": "0a:00:00",
+			})
+			CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, sbGlobal.Endpoint)
+
+			name := fmt.Sprintf("ovnglobalconfig-%s", uuid.New().String())
+			instance := CreateOVNGlobalConfig(namespace, name, map[string]interface{}{
+				"options": map[string]interface{}{
+					"mac_prefix":            "0a:58:0a",
+					"use_logical_dp_groups": "true",
+				},
+				"ipsec": true,
+			})
+			OVNGlobalConfigName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("applies the options and the ipsec flag", func() {
+			th.ExpectCondition(
+				OVNGlobalConfigName,
+				ConditionGetterFunc(OVNGlobalConfigConditionGetter),
+				v1beta1.OVNGlobalConfigAppliedCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(nbGlobal.Options()).To(Equal(map[string]string{
+				"mac_prefix":            "0a:58:0a",
+				"use_logical_dp_groups": "true",
+				"e2e_test_option":       "kept",
+			}))
+			Expect(nbGlobal.IPsec()).To(BeTrue())
+			Expect(sbGlobal.Options()).To(Equal(map[string]string{
+				"mac_prefix":            "0a:58:0a",
+				"use_logical_dp_groups": "true",
+			}))
+			Expect(sbGlobal.IPsec()).To(BeTrue())
+			Expect(GetOVNGlobalConfig(OVNGlobalConfigName).Status.ManagedOptions).To(Equal(
+				[]string{"mac_prefix", "use_logical_dp_groups"}))
+			Expect(GetOVNGlobalConfig(OVNGlobalConfigName).Status.Drift).To(BeEmpty())
+		})
+
+		It("removes the options dropped from the spec", func() {
+			th.ExpectCondition(
+				OVNGlobalConfigName,
+				ConditionGetterFunc(OVNGlobalConfigConditionGetter),
+				v1beta1.OVNGlobalConfigAppliedCondition,
+				corev1.ConditionTrue,
+			)
+			Eventually(func(g Gomega) {
+				config := GetOVNGlobalConfig(OVNGlobalConfigName)
+				delete(config.Spec.Options, "use_logical_dp_groups")
+				g.Expect(k8sClient.Update(ctx, config)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(nbGlobal.Options()).To(Equal(map[string]string{
+					"mac_prefix":      "0a:58:0a",
+					"e2e_test_option": "kept",
+				}))
+				g.Expect(sbGlobal.Options()).To(Equal(map[string]string{
+					"mac_prefix": "0a:58:0a",
+				}))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("restores and reports the values changed in the database", func() {
+			th.ExpectCondition(
+				OVNGlobalConfigName,
+				ConditionGetterFunc(OVNGlobalConfigConditionGetter),
+				v1beta1.OVNGlobalConfigAppliedCondition,
+				corev1.ConditionTrue,
+			)
+			nbGlobal.SetOption("mac_prefix", "0a:00:00")
+
+			// any update of the CR triggers a check before the resync interval
+			Eventually(func(g Gomega) {
+				config := GetOVNGlobalConfig(OVNGlobalConfigName)
+				config.Annotations = map[string]string{"test": "resync"}
+				g.Expect(k8sClient.Update(ctx, config)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(nbGlobal.Options()["mac_prefix"]).To(Equal("0a:58:0a"))
+				config := GetOVNGlobalConfig(OVNGlobalConfigName)
+				g.Expect(config.Status.Drift).To(Equal([]string{"NB_Global options:mac_prefix"}))
+				g.Expect(config.Status.LastDriftTime).NotTo(BeNil())
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("restores and reports the values changed in the SB database", func() {
+			th.ExpectCondition(
+				OVNGlobalConfigName,
+				ConditionGetterFunc(OVNGlobalConfigConditionGetter),
+				v1beta1.OVNGlobalConfigAppliedCondition,
+				corev1.ConditionTrue,
+			)
+			sbGlobal.SetOption("use_logical_dp_groups", "false")
+
+			Eventually(func(g Gomega) {
+				config := GetOVNGlobalConfig(OVNGlobalConfigName)
+				config.Annotations = map[string]string{"test": "resync"}
+				g.Expect(k8sClient.Update(ctx, config)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(sbGlobal.Options()["use_logical_dp_groups"]).To(Equal("true"))
+				config := GetOVNGlobalConfig(OVNGlobalConfigName)
+				g.Expect(config.Status.Drift).To(Equal([]string{"SB_Global options:use_logical_dp_groups"}))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+})
@@ -26,9 +26,14 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
 	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
 )
 
 var _ = Describe("OVNNorthd controller", func() {
@@ -52,6 +57,13 @@ var _ = Describe("OVNNorthd controller", func() {
 			Expect(OVNNorthd.Status.ReadyCount).To(Equal(int32(0)))
 		})
 
+		It("should observe the spec generation", func() {
+			Eventually(func(g Gomega) {
+				OVNNorthd := GetOVNNorthd(OVNNorthdName)
+				g.Expect(OVNNorthd.Status.ObservedGeneration).To(Equal(OVNNorthd.Generation))
+			}, timeout, interval).Should(Succeed())
+		})
+
 		It("should have a finalizer", func() {
 			// the reconciler loop adds the finalizer so we have to wait for
 			// it to run
@@ -69,7 +81,92 @@ var _ = Describe("OVNNorthd controller", func() {
 			)
 		})
 
+		It("reports that the OVNDBClusters are missing", func() {
+			th.ExpectConditionWithDetails(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNNorthdNBDBReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				fmt.Sprintf("OVN NB DB cluster in namespace %s not found", namespace),
+			)
+			th.ExpectCondition(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNNorthdSBDBReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+
 		When("OVNDBCluster instances are available", func() {
+			It("reports the OVNDBClusters not ready while they aren't", func() {
+				dbs := CreateOVNDBClusters(namespace, "")
+				DeferCleanup(DeleteOVNDBClusters, dbs)
+
+				th.ExpectConditionWithDetails(
+					OVNNorthdName,
+					ConditionGetterFunc(OVNNorthdConditionGetter),
+					v1beta1.OVNNorthdNBDBReadyCondition,
+					corev1.ConditionFalse,
+					condition.RequestedReason,
+					fmt.Sprintf("OVN NB DB cluster %s/%s not ready yet", dbs[0].Namespace, dbs[0].Name),
+				)
+				th.ExpectCondition(
+					OVNNorthdName,
+					ConditionGetterFunc(OVNNorthdConditionGetter),
+					v1beta1.OVNNorthdSBDBReadyCondition,
+					corev1.ConditionFalse,
+				)
+				// the instances run against a published cluster, they reconnect once it is ready
+				th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+			})
+
+			It("reports the OVNDBClusters ready", func() {
+				nbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.NBDBType, StartFakeOVSDB(map[string]string{}).Endpoint)
+				CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, StartFakeOVSDB(map[string]string{}).Endpoint)
+
+				th.ExpectConditionWithDetails(
+					OVNNorthdName,
+					ConditionGetterFunc(OVNNorthdConditionGetter),
+					v1beta1.OVNNorthdNBDBReadyCondition,
+					corev1.ConditionTrue,
+					condition.ReadyReason,
+					fmt.Sprintf("OVN NB DB cluster %s/%s ready", nbCluster.Namespace, nbCluster.Name),
+				)
+				th.ExpectCondition(
+					OVNNorthdName,
+					ConditionGetterFunc(OVNNorthdConditionGetter),
+					v1beta1.OVNNorthdSBDBReadyCondition,
+					corev1.ConditionTrue,
+				)
+			})
+
+			It("doesn't roll out the Deployment when the remotes are only reordered", func() {
+				dbs := CreateOVNDBClusters(namespace, "")
+				DeferCleanup(DeleteOVNDBClusters, dbs)
+				setNBRemotes := func(remotes string) {
+					Eventually(func(g Gomega) {
+						ovndbcluster := GetOVNDBCluster(dbs[0])
+						ovndbcluster.Status.InternalDBAddress = remotes
+						g.Expect(k8sClient.Status().Update(ctx, ovndbcluster)).Should(Succeed())
+					}, timeout, interval).Should(Succeed())
+				}
+
+				deplName := types.NamespacedName{Namespace: namespace, Name: "ovn-northd"}
+				setNBRemotes("tcp:10.1.1.2:6641,tcp:10.1.1.1:6641")
+				Eventually(func(g Gomega) {
+					depl := th.GetDeployment(deplName)
+					g.Expect(depl.Spec.Template.Spec.Containers[0].Args).To(
+						ContainElement("--ovnnb-db=tcp:10.1.1.1:6641,tcp:10.1.1.2:6641"))
+				}, timeout, interval).Should(Succeed())
+				generation := th.GetDeployment(deplName).Generation
+
+				setNBRemotes("tcp:10.1.1.1:6641,tcp:10.1.1.2:6641")
+				Consistently(func(g Gomega) {
+					g.Expect(th.GetDeployment(deplName).Generation).To(Equal(generation))
+				}, timeout, interval).Should(Succeed())
+			})
+
 			It("should create a Deployment with the ovn connection CLI args set based on the OVNDBCluster", func() {
 				dbs := CreateOVNDBClusters(namespace, "")
 				DeferCleanup(DeleteOVNDBClusters, dbs)
@@ -85,6 +182,19 @@ var _ = Describe("OVNNorthd controller", func() {
 					"--ovnsb-db=tcp:10.1.1.1:6642",
 				}))
 			})
+
+			It("should probe the NB and SB connections", func() {
+				dbs := CreateOVNDBClusters(namespace, "")
+				DeferCleanup(DeleteOVNDBClusters, dbs)
+
+				depl := th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+				container := depl.Spec.Template.Spec.Containers[0]
+				Expect(container.ReadinessProbe.Exec.Command[2]).To(ContainSubstring("status | grep -qE"))
+				Expect(container.ReadinessProbe.Exec.Command[2]).To(ContainSubstring("nb-connection-status"))
+				Expect(container.LivenessProbe.Exec.Command[2]).To(ContainSubstring("sb-connection-status"))
+				// restarted after the default connection timeout of 60 seconds
+				Expect(container.LivenessProbe.FailureThreshold).To(Equal(int32(20)))
+			})
 		})
 
 	})
@@ -248,4 +358,481 @@ var _ = Describe("OVNNorthd controller", func() {
 		})
 	})
 
+	When("OVNNorthd references OVNDBClusters of another namespace", func() {
+		var dbs []types.NamespacedName
+		BeforeEach(func() {
+			dbNamespace := uuid.New().String()
+			th.CreateNamespace(dbNamespace)
+			DeferCleanup(th.DeleteNamespace, dbNamespace)
+			dbs = CreateOVNDBClusters(dbNamespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+
+			spec := GetDefaultOVNNorthdSpec()
+			spec["nbClusterRef"] = map[string]interface{}{"name": dbs[0].Name, "namespace": dbs[0].Namespace}
+			spec["sbClusterRef"] = map[string]interface{}{"name": dbs[1].Name, "namespace": dbs[1].Namespace}
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			instance := CreateOVNNorthd(namespace, name, spec)
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("should create a Deployment connecting to the referenced OVNDBClusters", func() {
+			depl := th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+			Expect(depl.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+				"-vfile:off", "-vconsole:info",
+				"--ovnnb-db=tcp:10.1.1.1:6641",
+				"--ovnsb-db=tcp:10.1.1.1:6642",
+			}))
+		})
+
+		It("rejects the deletion of the referenced OVNDBClusters", func() {
+			err := k8sClient.Delete(ctx, GetOVNDBCluster(dbs[0]))
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("still used by OVNNorthd/" + namespace + "/"))
+		})
+	})
+
+	When("A OVNNorthd instance is created with TLS", func() {
+		var OVNNorthdName types.NamespacedName
+		var certSecretName types.NamespacedName
+		BeforeEach(func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			certSecretName = types.NamespacedName{Namespace: namespace, Name: "ovnnorthd-tls"}
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["tls"] = map[string]interface{}{
+				"secretName": certSecretName.Name,
+			}
+			instance := CreateOVNNorthd(namespace, name, spec)
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("reports that the TLS secret is missing", func() {
+			th.ExpectCondition(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				condition.InputReadyCondition,
+				corev1.ConditionFalse,
+			)
+		})
+
+		It("mounts the certificates and passes them to ovn-northd", func() {
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(certSecretName))
+
+			depl := th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+			th.AssertVolumeExists("ovn-northd-tls", depl.Spec.Template.Spec.Volumes)
+			container := depl.Spec.Template.Spec.Containers[0]
+			th.AssertVolumeMountExists("ovn-northd-tls", "tls.key", container.VolumeMounts)
+			Expect(container.Args).To(ContainElements(
+				"--private-key=/etc/pki/tls/private/ovnnorthd.key",
+				"--certificate=/etc/pki/tls/certs/ovnnorthd.crt",
+				"--ca-cert=/etc/pki/tls/certs/ovnnorthdca.crt",
+			))
+			th.AssertVolumeExists("ovn-northd-ca-bundle", depl.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("ovn-northd-ca-bundle", "ca.crt", container.VolumeMounts)
+		})
+	})
+
+	When("A OVNNorthd instance with TLS connects to an external SB database serving SSL", func() {
+		var OVNNorthdName types.NamespacedName
+		BeforeEach(func() {
+			certSecretName := types.NamespacedName{Namespace: namespace, Name: "ovnnorthd-tls"}
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(certSecretName))
+			externalCAName := types.NamespacedName{Namespace: namespace, Name: "external-sb-ca"}
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateSecret(externalCAName, map[string][]byte{"ca.crt": []byte("external-ca")}))
+
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["tls"] = map[string]interface{}{
+				"secretName": certSecretName.Name,
+			}
+			instance := CreateOVNNorthd(namespace, name, spec)
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+
+			CreateExternalOVNDBCluster(namespace, v1beta1.NBDBType)
+			sbSpec := GetDefaultOVNDBClusterSpec()
+			sbSpec["dbType"] = v1beta1.SBDBType
+			sbSpec["mode"] = v1beta1.ExternalMode
+			sbSpec["external"] = map[string]interface{}{"endpoint": "ssl:127.0.0.1:6642"}
+			sbSpec["tls"] = map[string]interface{}{"secretName": externalCAName.Name}
+			sbCluster := CreateOVNDBCluster(namespace, fmt.Sprintf("ovn-%s", uuid.New().String()), sbSpec)
+			DeferCleanup(th.DeleteInstance, sbCluster)
+		})
+
+		It("trusts the CA of the external database on top of its own", func() {
+			Eventually(func(g Gomega) {
+				cm := th.GetConfigMap(types.NamespacedName{Namespace: namespace, Name: OVNNorthdName.Name + "-ca-bundle"})
+				g.Expect(cm.Data["ca.crt"]).To(Equal("Zm9v\nexternal-ca\n"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNNorthd instance is created with tuning settings", func() {
+		var nbDB *FakeOVSDB
+		BeforeEach(func() {
+			nbDB = StartFakeOVSDB(map[string]string{"northd_probe_interval": "5000"})
+			nbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.NBDBType, nbDB.Endpoint)
+			sbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, StartFakeOVSDB(map[string]string{}).Endpoint)
+
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["nbClusterRef"] = map[string]interface{}{"name": nbCluster.Name}
+			spec["sbClusterRef"] = map[string]interface{}{"name": sbCluster.Name}
+			spec["tuning"] = map[string]interface{}{
+				"nThreads":      4,
+				"probeInterval": 10000,
+			}
+			spec["resources"] = map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "4"},
+			}
+			instance := CreateOVNNorthd(namespace, name, spec)
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("builds the logical flows in parallel", func() {
+			depl := th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+			container := depl.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).To(ContainElement("--n-threads=4"))
+			// the hook only pauses a starting instance, it can't fail on the NB database
+			Expect(container.Lifecycle.PostStart.Exec.Command[2]).NotTo(ContainSubstring("ovn-nbctl"))
+		})
+
+		It("stores the probe interval in NB_Global", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(nbDB.Options()).To(HaveKeyWithValue("northd_probe_interval", "10000"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNGlobalConfig sets the probe interval of the OVNNorthd tuning", func() {
+		var nbDB *FakeOVSDB
+		BeforeEach(func() {
+			nbDB = StartFakeOVSDB(map[string]string{"northd_probe_interval": "5000"})
+			nbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.NBDBType, nbDB.Endpoint)
+			sbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, StartFakeOVSDB(map[string]string{}).Endpoint)
+
+			globalConfig := CreateOVNGlobalConfig(namespace, fmt.Sprintf("ovnglobalconfig-%s", uuid.New().String()), map[string]interface{}{
+				"nbClusterRef": map[string]interface{}{"name": nbCluster.Name},
+				"sbClusterRef": map[string]interface{}{"name": sbCluster.Name},
+				"options":      map[string]interface{}{"northd_probe_interval": "20000"},
+			})
+			DeferCleanup(th.DeleteInstance, globalConfig)
+
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["nbClusterRef"] = map[string]interface{}{"name": nbCluster.Name}
+			spec["sbClusterRef"] = map[string]interface{}{"name": sbCluster.Name}
+			spec["tuning"] = map[string]interface{}{"probeInterval": 10000}
+			instance := CreateOVNNorthd(namespace, name, spec)
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("leaves the probe interval to the OVNGlobalConfig", func() {
+			Eventually(func(g Gomega) {
+				g.Expect(nbDB.Options()).To(HaveKeyWithValue("northd_probe_interval", "20000"))
+			}, timeout, interval).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(nbDB.Options()).To(HaveKeyWithValue("northd_probe_interval", "20000"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNNorthd instance requests less than a CPU without parallel threads", func() {
+		It("is accepted by the webhook", func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			spec := GetDefaultOVNNorthdSpec()
+			spec["tuning"] = map[string]interface{}{"probeInterval": 10000}
+			spec["resources"] = map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "500m"},
+			}
+			instance := CreateOVNNorthd(namespace, fmt.Sprintf("ovnnorthd-%s", uuid.New().String()), spec)
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+	})
+
+	When("A OVNNorthd instance requests less CPU than threads", func() {
+		It("is rejected by the webhook", func() {
+			spec := GetDefaultOVNNorthdSpec()
+			spec["tuning"] = map[string]interface{}{"nThreads": 4}
+			spec["resources"] = map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "2"},
+			}
+			raw := map[string]interface{}{
+				"apiVersion": "ovn.openstack.org/v1beta1",
+				"kind":       "OVNNorthd",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("ovnnorthd-%s", uuid.New().String()),
+					"namespace": namespace,
+				},
+				"spec": spec,
+			}
+			unstructuredObj := &unstructured.Unstructured{Object: raw}
+			_, err := controllerutil.CreateOrPatch(
+				ctx, k8sClient, unstructuredObj, func() error { return nil })
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("exceeds the 2 CPU requested in resources"))
+		})
+	})
+
+	When("A OVNNorthd instance is created with the metrics exporter", func() {
+		var OVNNorthdName types.NamespacedName
+		BeforeEach(func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["metrics"] = map[string]interface{}{
+				"enabled": true,
+			}
+			instance := CreateOVNNorthd(namespace, name, spec)
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("runs the exporter next to ovn-northd sharing its control socket", func() {
+			scriptsCM := types.NamespacedName{Namespace: namespace, Name: OVNNorthdName.Name + "-scripts"}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(scriptsCM).Data["metrics_exporter.py"]).Should(
+					ContainSubstring("PORT = 9476"))
+			}, timeout, interval).Should(Succeed())
+
+			depl := th.GetDeployment(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+			Expect(depl.Spec.Template.Spec.Containers).To(HaveLen(2))
+			exporter := depl.Spec.Template.Spec.Containers[1]
+			Expect(exporter.Name).To(Equal("metrics-exporter"))
+			Expect(exporter.Ports[0].ContainerPort).To(Equal(int32(9476)))
+			th.AssertVolumeExists("rundir", depl.Spec.Template.Spec.Volumes)
+			th.AssertVolumeMountExists("rundir", "", depl.Spec.Template.Spec.Containers[0].VolumeMounts)
+			th.AssertVolumeMountExists("rundir", "", exporter.VolumeMounts)
+		})
+
+		It("deletes the metrics Service once disabled", func() {
+			metricsSvc := types.NamespacedName{Namespace: namespace, Name: "ovn-northd-metrics"}
+			Eventually(func(g Gomega) {
+				svc := &corev1.Service{}
+				g.Expect(k8sClient.Get(ctx, metricsSvc, svc)).Should(Succeed())
+				g.Expect(svc.Spec.Selector).To(Equal(map[string]string{"service": "ovn-northd"}))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.Metrics.Enabled = false
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, metricsSvc, &corev1.Service{})
+				g.Expect(k8s_errors.IsNotFound(err)).Should(BeTrue())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNNorthd instance is paused", func() {
+		var OVNNorthdName types.NamespacedName
+		BeforeEach(func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			instance := CreateOVNNorthd(namespace, name, GetDefaultOVNNorthdSpec())
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("stores the pause state for the starting pods without a rollout", func() {
+			pauseCM := types.NamespacedName{Namespace: namespace, Name: OVNNorthdName.Name + "-pause"}
+			deplName := types.NamespacedName{Namespace: namespace, Name: "ovn-northd"}
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(pauseCM).Data["paused"]).Should(Equal("false"))
+			}, timeout, interval).Should(Succeed())
+			depl := th.GetDeployment(deplName)
+			th.AssertVolumeMountExists("pause", "", depl.Spec.Template.Spec.Containers[0].VolumeMounts)
+			Expect(depl.Spec.Template.Spec.Containers[0].Lifecycle.PostStart.Exec.Command[2]).To(
+				ContainSubstring("/etc/ovn-northd/pause/paused"))
+			generation := depl.Generation
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.Paused = true
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(th.GetConfigMap(pauseCM).Data["paused"]).Should(Equal("true"))
+				g.Expect(GetOVNNorthd(OVNNorthdName).Status.Paused).Should(BeTrue())
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNNorthdPausedCondition,
+				corev1.ConditionTrue,
+			)
+			Expect(th.GetDeployment(deplName).Generation).To(Equal(generation))
+		})
+
+		It("waits for the running instances which are not ready", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ovn-northd-" + uuid.New().String()[:8],
+					Namespace: namespace,
+					Labels:    map[string]string{"service": "ovn-northd"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "ovn-northd", Image: "ovn-northd"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pod)
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: namespace}, pod)).Should(Succeed())
+				pod.Status.Phase = corev1.PodRunning
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name:  "ovn-northd",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}}
+				g.Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.Paused = true
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			// the instance can't be reached from the test environment, it stays pending
+			th.ExpectConditionWithDetails(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNNorthdPausedCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				fmt.Sprintf(v1beta1.OVNNorthdPausingMessage, pod.Name),
+			)
+			Expect(GetOVNNorthd(OVNNorthdName).Status.Paused).Should(BeFalse())
+		})
+
+		It("removes the Paused condition once resumed", func() {
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.Paused = true
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNNorthdPausedCondition,
+				corev1.ConditionTrue,
+			)
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.Paused = false
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				g.Expect(northd.Status.Paused).Should(BeFalse())
+				g.Expect(northd.Status.Conditions.Has(v1beta1.OVNNorthdPausedCondition)).Should(BeFalse())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNNorthd instance runs a single replica", func() {
+		var OVNNorthdName types.NamespacedName
+		BeforeEach(func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			instance := CreateOVNNorthd(namespace, name, GetDefaultOVNNorthdSpec())
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("forgets the roles recorded while it had more replicas", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ovn-northd-" + uuid.New().String()[:8],
+					Namespace: namespace,
+					Labels:    map[string]string{"service": "ovn-northd", "ovn-role": "active"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "ovn-northd", Image: "ovn-northd"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pod)
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Status.ActiveInstance = "ovn-northd-gone"
+				northd.Status.Instances = []v1beta1.OVNNorthdInstance{{Name: "ovn-northd-gone", Role: "active"}}
+				g.Expect(k8sClient.Status().Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+			// any change of the spec triggers a reconcile
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				northd.Spec.ConfigLagThreshold++
+				g.Expect(k8sClient.Update(ctx, northd)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				g.Expect(northd.Status.ActiveInstance).Should(BeEmpty())
+				g.Expect(northd.Status.Instances).Should(BeEmpty())
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: namespace}, pod)).Should(Succeed())
+				g.Expect(pod.Labels).ShouldNot(HaveKey("ovn-role"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNNorthd instance propagates the NB configuration", func() {
+		var OVNNorthdName types.NamespacedName
+		var nbDB *FakeOVSDB
+		BeforeEach(func() {
+			nbDB = StartFakeOVSDB(map[string]string{})
+			nbDB.SetNBGlobal("nb_cfg", 5)
+			nbDB.SetNBGlobal("sb_cfg", 5)
+			nbDB.SetNBGlobal("hv_cfg", 4)
+			nbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.NBDBType, nbDB.Endpoint)
+			sbCluster := CreateExternalOVNDBClusterAt(namespace, v1beta1.SBDBType, StartFakeOVSDB(map[string]string{}).Endpoint)
+
+			name := fmt.Sprintf("ovnnorthd-%s", uuid.New().String())
+			spec := GetDefaultOVNNorthdSpec()
+			spec["nbClusterRef"] = map[string]interface{}{"name": nbCluster.Name}
+			spec["sbClusterRef"] = map[string]interface{}{"name": sbCluster.Name}
+			spec["configLagThreshold"] = 1
+			instance := CreateOVNNorthd(namespace, name, spec)
+			OVNNorthdName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+			th.SimulateDeploymentReplicaReady(types.NamespacedName{Namespace: namespace, Name: "ovn-northd"})
+		})
+
+		It("reports the lag of the chassis until they catch up", func() {
+			Eventually(func(g Gomega) {
+				propagation := GetOVNNorthd(OVNNorthdName).Status.ConfigPropagation
+				g.Expect(propagation).NotTo(BeNil())
+				g.Expect(propagation.NBCfg).To(Equal(int64(5)))
+				g.Expect(propagation.SBCfg).To(Equal(int64(5)))
+				g.Expect(propagation.HVCfg).To(Equal(int64(4)))
+				g.Expect(propagation.SBLag).To(BeNil())
+				g.Expect(propagation.HVLag).NotTo(BeNil())
+				g.Expect(propagation.HVLag.NBCfg).To(Equal(int64(5)))
+			}, timeout, interval).Should(Succeed())
+			th.ExpectCondition(
+				OVNNorthdName,
+				ConditionGetterFunc(OVNNorthdConditionGetter),
+				v1beta1.OVNConfigLaggingCondition,
+				corev1.ConditionTrue,
+			)
+
+			nbDB.SetNBGlobal("hv_cfg", 5)
+			Eventually(func(g Gomega) {
+				northd := GetOVNNorthd(OVNNorthdName)
+				g.Expect(northd.Status.ConfigPropagation.HVLag).To(BeNil())
+				g.Expect(northd.Status.Conditions.Has(v1beta1.OVNConfigLaggingCondition)).Should(BeFalse())
+			}, timeout, interval).Should(Succeed())
+		})
+	})
 })
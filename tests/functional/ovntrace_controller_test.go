@@ -0,0 +1,210 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package functional_test
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	. "github.com/openstack-k8s-operators/lib-common/modules/common/test/helpers"
+	"github.com/openstack-k8s-operators/ovn-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("OVNTrace controller", func() {
+
+	When("A OVNTrace instance is created without a flow", func() {
+		var OVNTraceName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovntrace-%s", uuid.New().String())
+			instance := CreateOVNTrace(namespace, name, map[string]interface{}{
+				"sourcePort": "lsp1",
+			})
+			OVNTraceName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("reports the missing destination", func() {
+			th.ExpectConditionWithDetails(
+				OVNTraceName,
+				ConditionGetterFunc(OVNTraceConditionGetter),
+				condition.InputReadyCondition,
+				corev1.ConditionFalse,
+				condition.ErrorReason,
+				"Input data error occurred either microflow, or sourcePort and destinationIP are required",
+			)
+		})
+	})
+
+	When("A OVNTrace instance is created without a SB OVNDBCluster", func() {
+		var OVNTraceName types.NamespacedName
+		BeforeEach(func() {
+			name := fmt.Sprintf("ovntrace-%s", uuid.New().String())
+			instance := CreateOVNTrace(namespace, name, map[string]interface{}{
+				"sourcePort":    "lsp1",
+				"destinationIP": "10.0.0.2",
+			})
+			OVNTraceName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("waits for the SB OVNDBCluster", func() {
+			th.ExpectConditionWithDetails(
+				OVNTraceName,
+				ConditionGetterFunc(OVNTraceConditionGetter),
+				condition.InputReadyCondition,
+				corev1.ConditionFalse,
+				condition.RequestedReason,
+				condition.InputReadyWaitingMessage,
+			)
+			Expect(GetOVNTrace(OVNTraceName).Status.Phase).To(Equal(v1beta1.TracePhasePending))
+		})
+	})
+
+	When("A OVNTrace instance is created with a microflow", func() {
+		var OVNTraceName types.NamespacedName
+		microflow := `inport == "lsp1" && eth.src == 00:00:00:00:00:01 && ip4.dst == 10.0.0.2`
+		BeforeEach(func() {
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			name := fmt.Sprintf("ovntrace-%s", uuid.New().String())
+			instance := CreateOVNTrace(namespace, name, map[string]interface{}{
+				"datapath":  "ls1",
+				"microflow": microflow,
+			})
+			OVNTraceName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("runs ovn-trace against the SB database in a job", func() {
+			job := th.GetJob(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-trace-1"})
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-ovn-nb-container-image"))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "MICROFLOW", Value: microflow}))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "DATAPATH", Value: "ls1"}))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "FORMAT", Value: "detailed"}))
+			Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(300)))
+
+			scripts := th.GetConfigMap(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-scripts"})
+			Expect(scripts.Data["trace.sh"]).To(ContainSubstring(`SB="tcp:10.1.1.1:6642"`))
+			Expect(scripts.Data["trace.sh"]).To(ContainSubstring("--timeout=30"))
+			Expect(GetOVNTrace(OVNTraceName).Status.Phase).To(Equal(v1beta1.TracePhaseRunning))
+		})
+
+		It("fails if the job fails", func() {
+			th.SimulateJobFailure(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-trace-1"})
+
+			th.ExpectCondition(
+				OVNTraceName,
+				ConditionGetterFunc(OVNTraceConditionGetter),
+				v1beta1.OVNTraceCompletedCondition,
+				corev1.ConditionFalse,
+			)
+			Expect(GetOVNTrace(OVNTraceName).Status.Phase).To(Equal(v1beta1.TracePhaseFailed))
+		})
+
+		It("traces again once the spec changed", func() {
+			th.SimulateJobFailure(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-trace-1"})
+			Eventually(func(g Gomega) {
+				g.Expect(GetOVNTrace(OVNTraceName).Status.Phase).To(Equal(v1beta1.TracePhaseFailed))
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				trace := GetOVNTrace(OVNTraceName)
+				trace.Spec.Format = "summary"
+				g.Expect(k8sClient.Update(ctx, trace)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			job := th.GetJob(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-trace-2"})
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(
+				ContainElement(corev1.EnvVar{Name: "FORMAT", Value: "summary"}))
+			Eventually(func(g Gomega) {
+				g.Expect(GetOVNTrace(OVNTraceName).Status.Phase).To(Equal(v1beta1.TracePhaseRunning))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+
+	When("A OVNTrace instance traces a SB OVNDBCluster serving SSL in another namespace", func() {
+		var OVNTraceName types.NamespacedName
+		BeforeEach(func() {
+			dbNamespace := uuid.New().String()
+			th.CreateNamespace(dbNamespace)
+			DeferCleanup(th.DeleteNamespace, dbNamespace)
+			dbs := CreateOVNDBClusters(dbNamespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			certSecretName := types.NamespacedName{Namespace: dbNamespace, Name: "ovndb-tls"}
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateCertSecret(certSecretName))
+			Eventually(func(g Gomega) {
+				sbCluster := GetOVNDBCluster(dbs[1])
+				sbCluster.Spec.TLS.SecretName = certSecretName.Name
+				g.Expect(k8sClient.Update(ctx, sbCluster)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			name := fmt.Sprintf("ovntrace-%s", uuid.New().String())
+			instance := CreateOVNTrace(namespace, name, map[string]interface{}{
+				"sbClusterRef": map[string]interface{}{"name": dbs[1].Name, "namespace": dbs[1].Namespace},
+				"microflow":    `inport == "lsp1" && eth.src == 00:00:00:00:00:01 && ip4.dst == 10.0.0.2`,
+			})
+			OVNTraceName = types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
+			DeferCleanup(th.DeleteInstance, instance)
+		})
+
+		It("mounts a copy of the client certificate in the namespace of the job", func() {
+			tlsSecret := th.GetSecret(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-sb-tls"})
+			Expect(tlsSecret.Data).To(HaveKey("tls.crt"))
+			Expect(tlsSecret.Data).To(HaveKey("ca.crt"))
+
+			job := th.GetJob(types.NamespacedName{Namespace: namespace, Name: OVNTraceName.Name + "-trace-1"})
+			th.AssertVolumeExists("sb-tls", job.Spec.Template.Spec.Volumes)
+			for _, volume := range job.Spec.Template.Spec.Volumes {
+				if volume.Name == "sb-tls" {
+					Expect(volume.Secret.SecretName).To(Equal(OVNTraceName.Name + "-sb-tls"))
+				}
+			}
+		})
+	})
+
+	When("A OVNTrace instance traces a SB database whose TLS Secret only holds the CA", func() {
+		It("doesn't pass a client certificate to ovn-sbctl and ovn-trace", func() {
+			caSecretName := types.NamespacedName{Namespace: namespace, Name: "external-sb-ca"}
+			DeferCleanup(k8sClient.Delete, ctx, th.CreateSecret(caSecretName, map[string][]byte{"ca.crt": []byte("ca")}))
+			dbs := CreateOVNDBClusters(namespace, "")
+			DeferCleanup(DeleteOVNDBClusters, dbs)
+			Eventually(func(g Gomega) {
+				sbCluster := GetOVNDBCluster(dbs[1])
+				sbCluster.Spec.TLS.SecretName = caSecretName.Name
+				g.Expect(k8sClient.Update(ctx, sbCluster)).Should(Succeed())
+			}, timeout, interval).Should(Succeed())
+
+			name := fmt.Sprintf("ovntrace-%s", uuid.New().String())
+			instance := CreateOVNTrace(namespace, name, map[string]interface{}{
+				"microflow": `inport == "lsp1" && eth.src == 00:00:00:00:00:01 && ip4.dst == 10.0.0.2`,
+			})
+			DeferCleanup(th.DeleteInstance, instance)
+
+			Eventually(func(g Gomega) {
+				scripts := th.GetConfigMap(types.NamespacedName{Namespace: namespace, Name: name + "-scripts"})
+				g.Expect(scripts.Data["trace.sh"]).To(ContainSubstring("--ca-cert=/etc/pki/tls/ovn/ca.crt"))
+				g.Expect(scripts.Data["trace.sh"]).NotTo(ContainSubstring("--private-key"))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+})